@@ -0,0 +1,74 @@
+package xelon
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon/api"
+)
+
+// Account holds the API settings and the token source for operations which are not bound to a single
+// machine, e.g. finding orphaned devices of the tenant.
+type Account struct {
+	APIBaseURL       string
+	CredentialHelper string
+	TenantID         string
+	Token            string
+}
+
+// LoadAccount resolves the account from the XELON_API_BASE_URL, XELON_CREDENTIAL_HELPER, XELON_TENANT_ID
+// and XELON_TOKEN environment variables and the profile with the given name in the configuration file,
+// in this order. An empty name selects the profile given by XELON_PROFILE or the default profile.
+func LoadAccount(profileName string) (*Account, error) {
+	p, err := loadProfile(firstString(profileName, os.Getenv("XELON_PROFILE")))
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Account{
+		APIBaseURL:       firstString(os.Getenv("XELON_API_BASE_URL"), p.APIBaseURL),
+		CredentialHelper: os.Getenv("XELON_CREDENTIAL_HELPER"),
+		TenantID:         firstString(os.Getenv("XELON_TENANT_ID"), p.TenantID),
+		Token:            os.Getenv("XELON_TOKEN"),
+	}
+	// the token source of the profile is only used if none is given explicitly
+	if a.Token == "" && a.CredentialHelper == "" {
+		a.Token = p.Token
+		a.CredentialHelper = p.CredentialHelper
+	}
+
+	if a.Token == "" && a.CredentialHelper == "" {
+		return nil, fmt.Errorf("XELON_TOKEN, XELON_CREDENTIAL_HELPER or a profile with a token source is required")
+	}
+	if a.Token != "" && a.CredentialHelper != "" {
+		return nil, fmt.Errorf("XELON_TOKEN and XELON_CREDENTIAL_HELPER cannot be used together")
+	}
+	return a, nil
+}
+
+// driver returns a driver for the device with the given localvmid which uses the account.
+func (a *Account) driver(machineName, localVMID string) *Driver {
+	d := NewDriver(machineName, "")
+	d.APIBaseURL = a.APIBaseURL
+	d.CredentialHelper = a.CredentialHelper
+	d.LocalVMID = localVMID
+	d.TenantID = a.TenantID
+	d.Token = a.Token
+	return d
+}
+
+// client returns an API client for the account. The tenant of the token is used if no tenant is set.
+func (a *Account) client() (*api.Client, error) {
+	client, err := a.driver("", "").getClient()
+	if err != nil {
+		return nil, err
+	}
+	if a.TenantID == "" {
+		tenant, _, err := client.Tenant.Get()
+		if err != nil {
+			return nil, err
+		}
+		a.TenantID = tenant.TenantIdentifier
+	}
+	return client, nil
+}
@@ -0,0 +1,88 @@
+package xelon
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// setEnv sets the environment variables and restores the previous values on teardown. Empty values
+// unset the variable.
+func setEnv(values map[string]string) (teardown func()) {
+	previous := make(map[string]*string, len(values))
+	for key, value := range values {
+		if old, ok := os.LookupEnv(key); ok {
+			previous[key] = &old
+		} else {
+			previous[key] = nil
+		}
+		if value == "" {
+			_ = os.Unsetenv(key)
+		} else {
+			_ = os.Setenv(key, value)
+		}
+	}
+	return func() {
+		for key, value := range previous {
+			if value == nil {
+				_ = os.Unsetenv(key)
+			} else {
+				_ = os.Setenv(key, *value)
+			}
+		}
+	}
+}
+
+var emptyAccountEnv = map[string]string{
+	"XELON_API_BASE_URL":      "",
+	"XELON_CREDENTIAL_HELPER": "",
+	"XELON_PROFILE":           "",
+	"XELON_TENANT_ID":         "",
+	"XELON_TOKEN":             "",
+}
+
+func TestLoadAccount_environment(t *testing.T) {
+	defer useConfig(t, testConfig)()
+	defer setEnv(emptyAccountEnv)()
+	defer setEnv(map[string]string{"XELON_TOKEN": "env-token", "XELON_TENANT_ID": "env-tenant"})()
+
+	account, err := LoadAccount("")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Account{TenantID: "env-tenant", Token: "env-token"}, account)
+}
+
+func TestLoadAccount_profile(t *testing.T) {
+	defer useConfig(t, testConfig)()
+	defer setEnv(emptyAccountEnv)()
+	defer setEnv(map[string]string{"XELON_PROFILE": "ci"})()
+
+	account, err := LoadAccount("")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Account{
+		APIBaseURL:       "https://vdc.example.com/api/service/",
+		CredentialHelper: "vault",
+		TenantID:         "tenantID",
+	}, account)
+}
+
+func TestLoadAccount_tokenAndCredentialHelper(t *testing.T) {
+	defer useConfig(t, "")()
+	defer setEnv(emptyAccountEnv)()
+	defer setEnv(map[string]string{"XELON_TOKEN": "env-token", "XELON_CREDENTIAL_HELPER": "vault"})()
+
+	_, err := LoadAccount("")
+
+	assert.Error(t, err)
+}
+
+func TestLoadAccount_missingToken(t *testing.T) {
+	defer useConfig(t, "")()
+	defer setEnv(emptyAccountEnv)()
+
+	_, err := LoadAccount("")
+
+	assert.Error(t, err)
+}
@@ -26,17 +26,41 @@ type Client struct {
 	UserAgent string   // User agent used when communicating with Xelon API.
 	Token     string   // Token for Xelon API.
 
+	// Recorder collects the requests which change resources instead of sending them, if it is set. Requests
+	// which only read resources are still sent, so lookups work as usual.
+	Recorder *RequestRecorder
+
 	common service // Reuse a single struct instead of allocating one for each service on the heap.
 
-	Devices *DevicesService
-	SSHs    *SSHsService
-	Tenant  *TenantService
+	Devices     *DevicesService
+	Disks       *DisksService
+	DNS         *DNSService
+	Firewalls   *FirewallsService
+	Hypervisors *HypervisorsService
+	IPAM        *IPAMService
+	Snapshots   *SnapshotsService
+	SSHs        *SSHsService
+	Templates   *TemplatesService
+	Tenant      *TenantService
 }
 
 type service struct {
 	client *Client
 }
 
+// RequestRecorder collects requests which were not sent, e.g. to show them in a dry run.
+type RequestRecorder struct {
+	Requests []RecordedRequest
+}
+
+// RecordedRequest represents a request which was not sent. The URL is relative to the BaseURL of the
+// client, passwords are redacted from the URL and the body.
+type RecordedRequest struct {
+	Body   string
+	Method string
+	URL    string
+}
+
 // NewClient returns a new Xelon API client. To use API methods provide the token.
 func NewClient(token string) *Client {
 	httpClient := &http.Client{
@@ -51,7 +75,14 @@ func NewClient(token string) *Client {
 	c.common.client = c
 
 	c.Devices = (*DevicesService)(&c.common)
+	c.Disks = (*DisksService)(&c.common)
+	c.DNS = (*DNSService)(&c.common)
+	c.Firewalls = (*FirewallsService)(&c.common)
+	c.Hypervisors = (*HypervisorsService)(&c.common)
+	c.IPAM = (*IPAMService)(&c.common)
+	c.Snapshots = (*SnapshotsService)(&c.common)
 	c.SSHs = (*SSHsService)(&c.common)
+	c.Templates = (*TemplatesService)(&c.common)
 	c.Tenant = (*TenantService)(&c.common)
 
 	return c
@@ -101,6 +132,10 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 // Do sends an API request and returns the API response. The API response is JSON decoded and stored in
 // the value pointed to by v, or returned as an error if an API error has occurred.
 func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
+	if c.Recorder != nil && req.Method != http.MethodGet {
+		return c.record(req)
+	}
+
 	req = req.WithContext(ctx)
 	resp, err := c.client.Do(req)
 	if err != nil {
@@ -169,6 +204,45 @@ func CheckResponse(resp *http.Response) error {
 	return errorResponse
 }
 
+// record adds the request to the recorder of the client and returns an empty successful response, so the
+// value passed to Do is left unchanged.
+func (c *Client) record(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		data, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+
+	c.Recorder.Requests = append(c.Recorder.Requests, RecordedRequest{
+		Body:   redactBody(body),
+		Method: req.Method,
+		URL:    strings.TrimPrefix(sanitizeURL(req.URL).String(), c.BaseURL.String()),
+	})
+	return &http.Response{
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+		Request:    req,
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+	}, nil
+}
+
+// redactBody redacts the password field of a JSON object.
+func redactBody(body []byte) string {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(body, &fields); err == nil {
+		if _, ok := fields["password"]; ok {
+			fields["password"] = "REDACTED"
+			if data, err := json.Marshal(fields); err == nil {
+				body = data
+			}
+		}
+	}
+	return strings.TrimSpace(string(body))
+}
+
 // sanitizeURL redacts the password parameter from the URL which may be exposed by the user.
 func sanitizeURL(uri *url.URL) *url.URL {
 	if uri == nil {
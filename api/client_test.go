@@ -52,3 +52,41 @@ func TestClient_Do_httpError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, 400, resp.StatusCode)
 }
+
+func TestClient_Do_recorder(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/vmlist", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[{"localvmid":"abc123"}]`)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %v %v", r.Method, r.URL)
+	})
+	client.Recorder = &RequestRecorder{}
+
+	devices, _, err := client.Devices.List("tenantID", nil)
+	assert.NoError(t, err)
+	assert.Len(t, devices, 1)
+	_, _, err = client.Devices.Create(&DeviceCreateConfiguration{Hostname: "ci-runner-1", Password: "secret"})
+	assert.NoError(t, err)
+	_, err = client.SSHs.Add("abc123", &SSHAddRequest{Name: "ci-runner-1", SSHKey: "ssh-rsa AAAA"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []RecordedRequest{
+		{
+			Method: http.MethodPost,
+			URL:    "vmlist/create?cpucores=0&disksize=0&displayname=&hostname=ci-runner-1&kubernetes_id=&memory=0&password=REDACTED&swapdisksize=0",
+		},
+		{
+			Body:   `{"name":"ci-runner-1","ssh_key":"ssh-rsa AAAA"}`,
+			Method: http.MethodPost,
+			URL:    "vmlist/abc123/ssh/add",
+		},
+	}, client.Recorder.Requests)
+}
+
+func TestRedactBody(t *testing.T) {
+	assert.Equal(t, `{"name":"admin","password":"REDACTED"}`, redactBody([]byte(`{"name":"admin","password":"secret"}`+"\n")))
+	assert.Equal(t, `{"name":"admin"}`, redactBody([]byte(`{"name":"admin"}`+"\n")))
+	assert.Equal(t, "", redactBody(nil))
+}
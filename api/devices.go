@@ -3,7 +3,10 @@ package api
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
 )
 
 const deviceBasePath = "vmlist"
@@ -13,11 +16,13 @@ type DevicesService service
 
 // Device represents a Xelon device.
 type Device struct {
-	CPU            int            `json:"cpu"`
-	LocalVMDetails LocalVMDetails `json:"localvmdetails,omitempty"`
-	Networks       []Network      `json:"networks,omitempty"`
-	Powerstate     bool           `json:"powerstate"`
-	RAM            int            `json:"ram"`
+	CPU            int               `json:"cpu"`
+	DiskSize       int               `json:"disksize,omitempty"`
+	LocalVMDetails LocalVMDetails    `json:"localvmdetails,omitempty"`
+	Networks       []Network         `json:"networks,omitempty"`
+	Powerstate     bool              `json:"powerstate"`
+	RAM            int               `json:"ram"`
+	Tags           map[string]string `json:"tags,omitempty"`
 }
 
 type ToolsStatus struct {
@@ -27,9 +32,43 @@ type ToolsStatus struct {
 }
 
 type Network struct {
-	IPAddress  string `json:"ip,omitempty"`
-	Label      string `json:"label,omitempty"`
-	MacAddress string `json:"macAddress,omitempty"`
+	IPAddress   string `json:"ip,omitempty"`
+	IPv6Address string `json:"ipv6,omitempty"`
+	Label       string `json:"label,omitempty"`
+	MacAddress  string `json:"macAddress,omitempty"`
+}
+
+// AddressFamily represents the family of an IP address.
+type AddressFamily string
+
+const (
+	AddressFamilyIPv4 AddressFamily = "ipv4"
+	AddressFamilyIPv6 AddressFamily = "ipv6"
+)
+
+// NetworkAddress represents an IP address of a network interface.
+type NetworkAddress struct {
+	Address string
+	Family  AddressFamily
+}
+
+// Addresses returns the IPv4 and IPv6 addresses of the network interface. The family is determined from
+// the address itself, since IPv6-only networks report their address in the ip field. Addresses which
+// cannot be parsed are skipped.
+func (n Network) Addresses() []NetworkAddress {
+	var addresses []NetworkAddress
+	for _, address := range []string{n.IPAddress, n.IPv6Address} {
+		ip := net.ParseIP(address)
+		if ip == nil {
+			continue
+		}
+		family := AddressFamilyIPv6
+		if ip.To4() != nil {
+			family = AddressFamilyIPv4
+		}
+		addresses = append(addresses, NetworkAddress{Address: ip.String(), Family: family})
+	}
+	return addresses
 }
 
 type DeviceCreateConfiguration struct {
@@ -37,10 +76,15 @@ type DeviceCreateConfiguration struct {
 	DiskSize     int
 	DisplayName  string
 	Hostname     string
+	HVSystemID   int
+	IPAddress    string
 	KubernetesID string
 	Memory       int
+	NetworkID    int
 	Password     string
 	SwapDiskSize int
+	Tags         map[string]string
+	TemplateID   int
 }
 
 type DeviceCreateResponse struct {
@@ -49,17 +93,61 @@ type DeviceCreateResponse struct {
 }
 
 type LocalVMDetails struct {
-	CreatedAt     string   `json:"created_at"`
-	HVSystemID    int      `json:"hv_system_id"`
-	ISOMounted    string   `json:"iso_mounted,omitempty"`
-	LocalVMID     string   `json:"localvmid"`
-	SSHKeys       []SSHKey `json:"ssh_keys,omitempty"`
-	State         int      `json:"state"`
-	TemplateID    int      `json:"template_id"`
-	UpdatedAt     string   `json:"updated_at"`
-	UserID        int      `json:"user_id"`
-	VMDisplayName string   `json:"vmdisplayname"`
-	VMHostname    string   `json:"vmhostname"`
+	CreatedAt     string            `json:"created_at"`
+	HVSystemID    int               `json:"hv_system_id"`
+	ISOMounted    string            `json:"iso_mounted,omitempty"`
+	LocalVMID     string            `json:"localvmid"`
+	SSHKeys       []SSHKey          `json:"ssh_keys,omitempty"`
+	State         int               `json:"state"`
+	Tags          map[string]string `json:"tags,omitempty"`
+	TemplateID    int               `json:"template_id"`
+	UpdatedAt     string            `json:"updated_at"`
+	UserID        int               `json:"user_id"`
+	VMDisplayName string            `json:"vmdisplayname"`
+	VMHostname    string            `json:"vmhostname"`
+}
+
+// DeviceReconfigureRequest represents the new resources of a device. Zero values are left unchanged.
+type DeviceReconfigureRequest struct {
+	CPUCores int `json:"cpucores,omitempty"`
+	DiskSize int `json:"disksize,omitempty"`
+	Memory   int `json:"memory,omitempty"`
+}
+
+// DeviceListOptions specifies the optional parameters to the List method.
+type DeviceListOptions struct {
+	Hostname string
+	// Tags restricts the list to devices which have all of the given tags.
+	Tags map[string]string
+}
+
+// DeviceCloneRequest represents the device which is created as a copy of another device. Zero values are
+// taken from the source device.
+type DeviceCloneRequest struct {
+	DisplayName string            `json:"displayname"`
+	Hostname    string            `json:"hostname"`
+	HVSystemID  int               `json:"hv_system_id,omitempty"`
+	NetworkID   int               `json:"network_id,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+// DeviceRenameRequest represents the new names of a device.
+type DeviceRenameRequest struct {
+	DisplayName string `json:"displayname"`
+	Hostname    string `json:"hostname"`
+}
+
+type deviceTagsRequest struct {
+	// Expected makes the update conditional on the current tags of the device.
+	Expected map[string]string `json:"expected,omitempty"`
+	Tags     map[string]string `json:"tags"`
+}
+
+// setTagParams adds tags as "tags[key]=value" query parameters.
+func setTagParams(params url.Values, tags map[string]string) {
+	for key, value := range tags {
+		params.Set(fmt.Sprintf("tags[%v]", key), value)
+	}
 }
 
 type DeviceRoot struct {
@@ -89,15 +177,65 @@ func (s *DevicesService) Get(tenantID, localVMID string) (*DeviceRoot, *http.Res
 	return deviceRoot, resp, nil
 }
 
+// List provides a list of all devices in the tenant, optionally filtered by opts.
+func (s *DevicesService) List(tenantID string, opts *DeviceListOptions) ([]LocalVMDetails, *http.Response, error) {
+	if tenantID == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+
+	params := url.Values{}
+	params.Set("tenant", tenantID)
+	if opts != nil && opts.Hostname != "" {
+		params.Set("hostname", opts.Hostname)
+	}
+	if opts != nil {
+		setTagParams(params, opts.Tags)
+	}
+	path := fmt.Sprintf("%v?%v", deviceBasePath, params.Encode())
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var devices []LocalVMDetails
+	resp, err := s.client.Do(context.Background(), req, &devices)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return devices, resp, nil
+}
+
 // Create makes a new device with given parameters.
 func (s *DevicesService) Create(config *DeviceCreateConfiguration) (*DeviceCreateResponse, *http.Response, error) {
 	if config == nil {
 		return nil, nil, ErrEmptyPayloadNotAllowed
 	}
 
-	path := fmt.Sprintf("%v/create?cpucores=%v&disksize=%v&displayname=%v&hostname=%v&kubernetes_id=%v&memory=%v&password=%v&swapdisksize=%v",
-		deviceBasePath, config.CPUCores, config.DiskSize, config.DisplayName, config.Hostname,
-		config.KubernetesID, config.Memory, config.Password, config.SwapDiskSize)
+	params := url.Values{}
+	params.Set("cpucores", strconv.Itoa(config.CPUCores))
+	params.Set("disksize", strconv.Itoa(config.DiskSize))
+	params.Set("displayname", config.DisplayName)
+	params.Set("hostname", config.Hostname)
+	params.Set("kubernetes_id", config.KubernetesID)
+	params.Set("memory", strconv.Itoa(config.Memory))
+	params.Set("password", config.Password)
+	params.Set("swapdisksize", strconv.Itoa(config.SwapDiskSize))
+	setTagParams(params, config.Tags)
+	if config.NetworkID != 0 {
+		params.Set("network_id", strconv.Itoa(config.NetworkID))
+	}
+	if config.HVSystemID != 0 {
+		params.Set("hv_system_id", strconv.Itoa(config.HVSystemID))
+	}
+	if config.IPAddress != "" {
+		params.Set("ip_address", config.IPAddress)
+	}
+	if config.TemplateID != 0 {
+		params.Set("template_id", strconv.Itoa(config.TemplateID))
+	}
+	path := fmt.Sprintf("%v/create?%v", deviceBasePath, params.Encode())
 
 	req, err := s.client.NewRequest(http.MethodPost, path, nil)
 	if err != nil {
@@ -113,6 +251,64 @@ func (s *DevicesService) Create(config *DeviceCreateConfiguration) (*DeviceCreat
 	return deviceCreateResponse, resp, nil
 }
 
+// Clone creates a new device as a copy of the disks and resources of device with specific localvmid.
+func (s *DevicesService) Clone(localVMID string, request *DeviceCloneRequest) (*DeviceCreateResponse, *http.Response, error) {
+	if localVMID == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+	if request == nil {
+		return nil, nil, ErrEmptyPayloadNotAllowed
+	}
+
+	path := fmt.Sprintf("%v/%v/clone", deviceBasePath, localVMID)
+
+	req, err := s.client.NewRequest(http.MethodPost, path, request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deviceCreateResponse := new(DeviceCreateResponse)
+	resp, err := s.client.Do(context.Background(), req, deviceCreateResponse)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return deviceCreateResponse, resp, nil
+}
+
+// Reconfigure changes CPU cores and memory of a device and grows its disk. The device must be stopped to change
+// CPU cores or memory, the disk size cannot be decreased.
+func (s *DevicesService) Reconfigure(tenantID, localVMID string, request *DeviceReconfigureRequest) (*http.Response, error) {
+	if tenantID == "" || localVMID == "" {
+		return nil, ErrEmptyArgument
+	}
+	if request == nil {
+		return nil, ErrEmptyPayloadNotAllowed
+	}
+
+	deviceRoot, resp, err := s.Get(tenantID, localVMID)
+	if err != nil {
+		return resp, err
+	}
+	device := deviceRoot.Device
+	if device.Powerstate &&
+		(request.CPUCores != 0 && request.CPUCores != device.CPU || request.Memory != 0 && request.Memory != device.RAM) {
+		return nil, ErrDeviceMustBeStopped
+	}
+	if request.DiskSize != 0 && request.DiskSize < device.DiskSize {
+		return nil, ErrDiskShrinkNotAllowed
+	}
+
+	path := fmt.Sprintf("%v/%v/reconfigure", deviceBasePath, localVMID)
+
+	req, err := s.client.NewRequest(http.MethodPost, path, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(context.Background(), req, nil)
+}
+
 // Delete removes a server.
 func (s *DevicesService) Delete(localVMID string) (*http.Response, error) {
 	if localVMID == "" {
@@ -160,3 +356,94 @@ func (s *DevicesService) Stop(localVMID string) (*http.Response, error) {
 
 	return s.client.Do(context.Background(), req, nil)
 }
+
+// UpdateTags replaces the tags of a device with specific localvmid.
+func (s *DevicesService) UpdateTags(localVMID string, tags map[string]string) (*http.Response, error) {
+	if localVMID == "" {
+		return nil, ErrEmptyArgument
+	}
+	if tags == nil {
+		return nil, ErrEmptyPayloadNotAllowed
+	}
+
+	path := fmt.Sprintf("%v/%v/tags", deviceBasePath, localVMID)
+
+	req, err := s.client.NewRequest(http.MethodPut, path, &deviceTagsRequest{Tags: tags})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(context.Background(), req, nil)
+}
+
+// CompareAndSwapTags replaces the tags of a device with specific localvmid only if its current tags equal
+// expected. Otherwise the API responds with 409 Conflict, so concurrent clients cannot both change the tags
+// of the same device.
+func (s *DevicesService) CompareAndSwapTags(localVMID string, expected, tags map[string]string) (*http.Response, error) {
+	if localVMID == "" {
+		return nil, ErrEmptyArgument
+	}
+	if expected == nil || tags == nil {
+		return nil, ErrEmptyPayloadNotAllowed
+	}
+
+	path := fmt.Sprintf("%v/%v/tags", deviceBasePath, localVMID)
+
+	req, err := s.client.NewRequest(http.MethodPut, path, &deviceTagsRequest{Expected: expected, Tags: tags})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(context.Background(), req, nil)
+}
+
+// Rename changes the hostname and the display name of a device with specific localvmid.
+func (s *DevicesService) Rename(localVMID string, request *DeviceRenameRequest) (*http.Response, error) {
+	if localVMID == "" {
+		return nil, ErrEmptyArgument
+	}
+	if request == nil {
+		return nil, ErrEmptyPayloadNotAllowed
+	}
+
+	path := fmt.Sprintf("%v/%v/rename", deviceBasePath, localVMID)
+
+	req, err := s.client.NewRequest(http.MethodPost, path, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(context.Background(), req, nil)
+}
+
+// Reboot restarts the guest operating system of a server with specific localvmid. The guest tools must be running.
+func (s *DevicesService) Reboot(localVMID string) (*http.Response, error) {
+	if localVMID == "" {
+		return nil, ErrEmptyArgument
+	}
+
+	path := fmt.Sprintf("%v/%v/reboot", deviceBasePath, localVMID)
+
+	req, err := s.client.NewRequest(http.MethodPost, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(context.Background(), req, nil)
+}
+
+// Reset performs a hard reset of a server with specific localvmid.
+func (s *DevicesService) Reset(localVMID string) (*http.Response, error) {
+	if localVMID == "" {
+		return nil, ErrEmptyArgument
+	}
+
+	path := fmt.Sprintf("%v/%v/reset", deviceBasePath, localVMID)
+
+	req, err := s.client.NewRequest(http.MethodPost, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(context.Background(), req, nil)
+}
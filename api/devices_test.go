@@ -1,6 +1,9 @@
 package api
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -26,6 +29,34 @@ func TestDevicesService_Get_emptyLocalVMID(t *testing.T) {
 	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
 }
 
+func TestDevicesService_List_emptyTenantID(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, _, err := client.Devices.List("", nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestDevicesService_List_hostnameFilter(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/vmlist", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "tenantID", r.URL.Query().Get("tenant"))
+		assert.Equal(t, "ci-runner-1", r.URL.Query().Get("hostname"))
+		assert.Empty(t, r.URL.Query().Get("hv_system_id"))
+		assert.Empty(t, r.URL.Query().Get("ip_address"))
+		_, _ = fmt.Fprint(w, `[{"localvmid":"abc123","vmhostname":"ci-runner-1"}]`)
+	})
+
+	devices, _, err := client.Devices.List("tenantID", &DeviceListOptions{Hostname: "ci-runner-1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []LocalVMDetails{{LocalVMID: "abc123", VMHostname: "ci-runner-1"}}, devices)
+}
+
 func TestDevicesService_Create_emptyDeviceCreateConfiguration(t *testing.T) {
 	client, _, _, teardown := setup()
 	defer teardown()
@@ -36,6 +67,47 @@ func TestDevicesService_Create_emptyDeviceCreateConfiguration(t *testing.T) {
 	assert.Equal(t, ErrEmptyPayloadNotAllowed.Error(), err.Error())
 }
 
+func TestDevicesService_Reconfigure_emptyArguments(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.Devices.Reconfigure("", "localVMID", &DeviceReconfigureRequest{})
+	assert.Equal(t, ErrEmptyArgument, err)
+
+	_, err = client.Devices.Reconfigure("tenantID", "", &DeviceReconfigureRequest{})
+	assert.Equal(t, ErrEmptyArgument, err)
+
+	_, err = client.Devices.Reconfigure("tenantID", "localVMID", nil)
+	assert.Equal(t, ErrEmptyPayloadNotAllowed, err)
+}
+
+func TestDevicesService_Reconfigure_preconditions(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"device":{"cpu":2,"ram":4,"disksize":50,"powerstate":true}}`)
+	})
+	mux.HandleFunc("/vmlist/localVMID/reconfigure", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		request := new(DeviceReconfigureRequest)
+		_ = json.NewDecoder(r.Body).Decode(request)
+		assert.Equal(t, &DeviceReconfigureRequest{CPUCores: 2, DiskSize: 100}, request)
+	})
+
+	_, err := client.Devices.Reconfigure("tenantID", "localVMID", &DeviceReconfigureRequest{CPUCores: 4})
+	assert.Equal(t, ErrDeviceMustBeStopped, err)
+
+	_, err = client.Devices.Reconfigure("tenantID", "localVMID", &DeviceReconfigureRequest{Memory: 8})
+	assert.Equal(t, ErrDeviceMustBeStopped, err)
+
+	_, err = client.Devices.Reconfigure("tenantID", "localVMID", &DeviceReconfigureRequest{DiskSize: 40})
+	assert.Equal(t, ErrDiskShrinkNotAllowed, err)
+
+	// disks can be grown while the device is running
+	_, err = client.Devices.Reconfigure("tenantID", "localVMID", &DeviceReconfigureRequest{CPUCores: 2, DiskSize: 100})
+	assert.NoError(t, err)
+}
+
 func TestDevicesService_Delete_emptyLocalVMID(t *testing.T) {
 	client, _, _, teardown := setup()
 	defer teardown()
@@ -65,3 +137,264 @@ func TestDevicesService_Stop_emptyLocalVMID(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
 }
+
+func TestDevicesService_Create_escapesParameters(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/vmlist/create", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "p&ss#w=rd", r.URL.Query().Get("password"))
+		assert.Equal(t, "ci-runner-1", r.URL.Query().Get("hostname"))
+		assert.Empty(t, r.URL.Query().Get("hv_system_id"))
+		assert.Empty(t, r.URL.Query().Get("ip_address"))
+		_, _ = fmt.Fprint(w, `{"device":{"localvmid":"abc123"},"ips":["10.0.0.1"]}`)
+	})
+
+	response, _, err := client.Devices.Create(&DeviceCreateConfiguration{Hostname: "ci-runner-1", Password: "p&ss#w=rd"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "abc123", response.Device.LocalVMID)
+}
+
+func TestDevicesService_Create_ipAddress(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/vmlist/create", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "10.0.0.20", r.URL.Query().Get("ip_address"))
+		assert.Equal(t, "7", r.URL.Query().Get("network_id"))
+		_, _ = fmt.Fprint(w, `{"device":{"localvmid":"abc123"},"ips":["10.0.0.20"]}`)
+	})
+
+	_, _, err := client.Devices.Create(&DeviceCreateConfiguration{IPAddress: "10.0.0.20", NetworkID: 7})
+
+	assert.NoError(t, err)
+}
+
+func TestDevicesService_Create_hvSystemID(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/vmlist/create", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "2", r.URL.Query().Get("hv_system_id"))
+		_, _ = fmt.Fprint(w, `{"device":{"localvmid":"abc123"},"ips":["10.0.0.1"]}`)
+	})
+
+	_, _, err := client.Devices.Create(&DeviceCreateConfiguration{HVSystemID: 2})
+
+	assert.NoError(t, err)
+}
+
+func TestDevicesService_Create_tags(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/vmlist/create", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.URL.Query().Get("tags[docker-machine]"))
+		assert.Equal(t, "platform", r.URL.Query().Get("tags[team]"))
+		_, _ = fmt.Fprint(w, `{"device":{"localvmid":"abc123"},"ips":["10.0.0.1"]}`)
+	})
+
+	_, _, err := client.Devices.Create(&DeviceCreateConfiguration{
+		Tags: map[string]string{"docker-machine": "true", "team": "platform"},
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestDevicesService_List_tagFilter(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/vmlist", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.URL.Query().Get("tags[docker-machine]"))
+		_, _ = fmt.Fprint(w, `[{"localvmid":"abc123","tags":{"docker-machine":"true","docker-machine-name":"ci-runner-1"}}]`)
+	})
+
+	devices, _, err := client.Devices.List("tenantID", &DeviceListOptions{Tags: map[string]string{"docker-machine": "true"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"docker-machine": "true", "docker-machine-name": "ci-runner-1"}, devices[0].Tags)
+}
+
+func TestDevicesService_UpdateTags_emptyArguments(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.Devices.UpdateTags("", map[string]string{})
+	assert.Equal(t, ErrEmptyArgument, err)
+
+	_, err = client.Devices.UpdateTags("abc123", nil)
+	assert.Equal(t, ErrEmptyPayloadNotAllowed, err)
+}
+
+func TestDevicesService_UpdateTags(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/vmlist/abc123/tags", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		request := new(deviceTagsRequest)
+		_ = json.NewDecoder(r.Body).Decode(request)
+		assert.Equal(t, map[string]string{"team": "platform"}, request.Tags)
+	})
+
+	_, err := client.Devices.UpdateTags("abc123", map[string]string{"team": "platform"})
+
+	assert.NoError(t, err)
+}
+
+func TestDevicesService_Clone_emptyArguments(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, _, err := client.Devices.Clone("", &DeviceCloneRequest{})
+	assert.Equal(t, ErrEmptyArgument, err)
+
+	_, _, err = client.Devices.Clone("golden", nil)
+	assert.Equal(t, ErrEmptyPayloadNotAllowed, err)
+}
+
+func TestDevicesService_Clone(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/vmlist/golden/clone", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		request := new(DeviceCloneRequest)
+		_ = json.NewDecoder(r.Body).Decode(request)
+		assert.Equal(t, &DeviceCloneRequest{DisplayName: "ci-runner-1", Hostname: "ci-runner-1", NetworkID: 5}, request)
+		_, _ = fmt.Fprint(w, `{"device":{"localvmid":"abc123"}}`)
+	})
+
+	response, _, err := client.Devices.Clone("golden", &DeviceCloneRequest{DisplayName: "ci-runner-1", Hostname: "ci-runner-1", NetworkID: 5})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "abc123", response.Device.LocalVMID)
+}
+
+func TestDevicesService_CompareAndSwapTags_emptyArguments(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.Devices.CompareAndSwapTags("", map[string]string{}, map[string]string{})
+	assert.Equal(t, ErrEmptyArgument, err)
+
+	_, err = client.Devices.CompareAndSwapTags("abc123", nil, map[string]string{})
+	assert.Equal(t, ErrEmptyPayloadNotAllowed, err)
+
+	_, err = client.Devices.CompareAndSwapTags("abc123", map[string]string{}, nil)
+	assert.Equal(t, ErrEmptyPayloadNotAllowed, err)
+}
+
+func TestDevicesService_CompareAndSwapTags(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/vmlist/abc123/tags", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		request := new(deviceTagsRequest)
+		_ = json.NewDecoder(r.Body).Decode(request)
+		assert.Equal(t, map[string]string{"pool": "default"}, request.Expected)
+		assert.Equal(t, map[string]string{"team": "platform"}, request.Tags)
+	})
+
+	_, err := client.Devices.CompareAndSwapTags("abc123", map[string]string{"pool": "default"}, map[string]string{"team": "platform"})
+
+	assert.NoError(t, err)
+}
+
+func TestDevicesService_CompareAndSwapTags_conflict(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/vmlist/abc123/tags", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	})
+
+	resp, err := client.Devices.CompareAndSwapTags("abc123", map[string]string{"pool": "default"}, map[string]string{"team": "platform"})
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+}
+
+func TestDevicesService_Rename_emptyArguments(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.Devices.Rename("", &DeviceRenameRequest{})
+	assert.Equal(t, ErrEmptyArgument, err)
+
+	_, err = client.Devices.Rename("abc123", nil)
+	assert.Equal(t, ErrEmptyPayloadNotAllowed, err)
+}
+
+func TestDevicesService_Rename(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/vmlist/abc123/rename", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		request := new(DeviceRenameRequest)
+		_ = json.NewDecoder(r.Body).Decode(request)
+		assert.Equal(t, &DeviceRenameRequest{DisplayName: "ci-runner-1", Hostname: "ci-runner-1"}, request)
+	})
+
+	_, err := client.Devices.Rename("abc123", &DeviceRenameRequest{DisplayName: "ci-runner-1", Hostname: "ci-runner-1"})
+
+	assert.NoError(t, err)
+}
+
+func TestDevicesService_Reboot_emptyLocalVMID(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.Devices.Reboot("")
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestDevicesService_Reboot(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/vmlist/localVMID/reboot", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+	})
+
+	_, err := client.Devices.Reboot("localVMID")
+
+	assert.NoError(t, err)
+}
+
+func TestDevicesService_Reset_emptyLocalVMID(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.Devices.Reset("")
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestNetwork_Addresses(t *testing.T) {
+	tests := map[string]struct {
+		network Network
+		want    []NetworkAddress
+	}{
+		"ipv4": {
+			network: Network{IPAddress: "10.0.0.10"},
+			want:    []NetworkAddress{{Address: "10.0.0.10", Family: AddressFamilyIPv4}},
+		},
+		"dual-stack": {
+			network: Network{IPAddress: "10.0.0.10", IPv6Address: "2001:DB8::10"},
+			want: []NetworkAddress{
+				{Address: "10.0.0.10", Family: AddressFamilyIPv4},
+				{Address: "2001:db8::10", Family: AddressFamilyIPv6},
+			},
+		},
+		"ipv6-only": {
+			network: Network{IPAddress: "2001:db8::10"},
+			want:    []NetworkAddress{{Address: "2001:db8::10", Family: AddressFamilyIPv6}},
+		},
+		"invalid": {
+			network: Network{IPAddress: "pending"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.network.Addresses())
+		})
+	}
+}
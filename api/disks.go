@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const diskBasePath = "disks"
+
+// DisksService handles communication with the disk related methods of the Xelon API.
+type DisksService service
+
+// Disk represents an additional disk attached to a Xelon device.
+type Disk struct {
+	CreatedAt string `json:"created_at,omitempty"`
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Size      int    `json:"size"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+type DiskAddRequest struct {
+	Name string `json:"name"`
+	Size int    `json:"size"`
+}
+
+// Add attaches a new disk to device with specific localvmid.
+func (s *DisksService) Add(localVMID string, diskAddRequest *DiskAddRequest) (*Disk, *http.Response, error) {
+	if localVMID == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+	if diskAddRequest == nil {
+		return nil, nil, ErrEmptyPayloadNotAllowed
+	}
+
+	path := fmt.Sprintf("%v/%v/%v/add", deviceBasePath, localVMID, diskBasePath)
+
+	req, err := s.client.NewRequest(http.MethodPost, path, diskAddRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	disk := new(Disk)
+	resp, err := s.client.Do(context.Background(), req, disk)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return disk, resp, nil
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisksService_Add_emptyLocalVMID(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, _, err := client.Disks.Add("", nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestDisksService_Add_emptyDiskAddRequest(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, _, err := client.Disks.Add("localVMID", nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyPayloadNotAllowed.Error(), err.Error())
+}
+
+func TestDisksService_Add(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/vmlist/localVMID/disks/add", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		request := new(DiskAddRequest)
+		_ = json.NewDecoder(r.Body).Decode(request)
+		assert.Equal(t, &DiskAddRequest{Name: "data-1", Size: 100}, request)
+		_, _ = fmt.Fprint(w, `{"id":7,"name":"data-1","size":100}`)
+	})
+
+	disk, _, err := client.Disks.Add("localVMID", &DiskAddRequest{Name: "data-1", Size: 100})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Disk{ID: 7, Name: "data-1", Size: 100}, disk)
+}
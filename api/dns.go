@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const (
+	dnsBasePath = "dns/zones"
+
+	DNSRecordTypeA    = "A"
+	DNSRecordTypeAAAA = "AAAA"
+	DNSRecordTypePTR  = "PTR"
+)
+
+// DNSService handles communication with the DNS related methods of the Xelon API.
+type DNSService service
+
+// DNSZone represents a DNS zone, e.g. "example.com" or "0.10.in-addr.arpa".
+type DNSZone struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// DNSRecord represents a record of a DNS zone. Name is relative to the zone.
+type DNSRecord struct {
+	Content string `json:"content"`
+	ID      int    `json:"id,omitempty"`
+	Name    string `json:"name"`
+	TTL     int    `json:"ttl,omitempty"`
+	Type    string `json:"type"`
+}
+
+// ListZones provides a list of DNS zones in the tenant.
+func (s *DNSService) ListZones(tenantID string) ([]DNSZone, *http.Response, error) {
+	if tenantID == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+
+	params := url.Values{}
+	params.Set("tenant", tenantID)
+	path := fmt.Sprintf("%v?%v", dnsBasePath, params.Encode())
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var zones []DNSZone
+	resp, err := s.client.Do(context.Background(), req, &zones)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return zones, resp, nil
+}
+
+// CreateRecord makes a new A, AAAA or PTR record in the zone.
+func (s *DNSService) CreateRecord(zoneID int, record *DNSRecord) (*DNSRecord, *http.Response, error) {
+	if zoneID == 0 {
+		return nil, nil, ErrEmptyArgument
+	}
+	if record == nil {
+		return nil, nil, ErrEmptyPayloadNotAllowed
+	}
+	switch record.Type {
+	case DNSRecordTypeA, DNSRecordTypeAAAA, DNSRecordTypePTR:
+	default:
+		return nil, nil, fmt.Errorf("unsupported DNS record type %q", record.Type)
+	}
+
+	path := fmt.Sprintf("%v/%v/records", dnsBasePath, zoneID)
+
+	req, err := s.client.NewRequest(http.MethodPost, path, record)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	createdRecord := new(DNSRecord)
+	resp, err := s.client.Do(context.Background(), req, createdRecord)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return createdRecord, resp, nil
+}
+
+// DeleteRecord removes a record from the zone.
+func (s *DNSService) DeleteRecord(zoneID, recordID int) (*http.Response, error) {
+	if zoneID == 0 || recordID == 0 {
+		return nil, ErrEmptyArgument
+	}
+
+	path := fmt.Sprintf("%v/%v/records/%v", dnsBasePath, zoneID, recordID)
+
+	req, err := s.client.NewRequest(http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(context.Background(), req, nil)
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDNSService_ListZones_emptyTenantID(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, _, err := client.DNS.ListZones("")
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestDNSService_ListZones(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/dns/zones", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "tenantID", r.URL.Query().Get("tenant"))
+		_, _ = fmt.Fprint(w, `[{"id":1,"name":"example.com"},{"id":2,"name":"0.10.in-addr.arpa"}]`)
+	})
+
+	zones, _, err := client.DNS.ListZones("tenantID")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []DNSZone{{ID: 1, Name: "example.com"}, {ID: 2, Name: "0.10.in-addr.arpa"}}, zones)
+}
+
+func TestDNSService_CreateRecord_invalidArguments(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, _, err := client.DNS.CreateRecord(0, &DNSRecord{Type: DNSRecordTypeA})
+	assert.Equal(t, ErrEmptyArgument, err)
+
+	_, _, err = client.DNS.CreateRecord(1, nil)
+	assert.Equal(t, ErrEmptyPayloadNotAllowed, err)
+
+	_, _, err = client.DNS.CreateRecord(1, &DNSRecord{Type: "MX"})
+	assert.Error(t, err)
+}
+
+func TestDNSService_CreateRecord(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/dns/zones/1/records", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		record := new(DNSRecord)
+		_ = json.NewDecoder(r.Body).Decode(record)
+		assert.Equal(t, DNSRecord{Content: "10.0.0.10", Name: "ci-runner-1", TTL: 300, Type: DNSRecordTypeA}, *record)
+		_, _ = fmt.Fprint(w, `{"id":5,"content":"10.0.0.10","name":"ci-runner-1","ttl":300,"type":"A"}`)
+	})
+
+	record, _, err := client.DNS.CreateRecord(1, &DNSRecord{Content: "10.0.0.10", Name: "ci-runner-1", TTL: 300, Type: DNSRecordTypeA})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, record.ID)
+}
+
+func TestDNSService_DeleteRecord_emptyArguments(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.DNS.DeleteRecord(1, 0)
+
+	assert.Equal(t, ErrEmptyArgument, err)
+}
+
+func TestDNSService_DeleteRecord(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/dns/zones/1/records/5", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+	})
+
+	_, err := client.DNS.DeleteRecord(1, 5)
+
+	assert.NoError(t, err)
+}
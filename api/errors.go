@@ -7,6 +7,8 @@ import (
 )
 
 var (
+	ErrDeviceMustBeStopped    = errors.New("(api) device must be stopped to change cpu cores or memory")
+	ErrDiskShrinkNotAllowed   = errors.New("(api) disk size cannot be decreased")
 	ErrEmptyArgument          = errors.New("(api) argument cannot be empty")
 	ErrEmptyPayloadNotAllowed = errors.New("(api) empty payload not allowed")
 )
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	firewallBasePath = "firewalls"
+
+	FirewallDirectionInbound = "inbound"
+	FirewallProtocolTCP      = "tcp"
+)
+
+// FirewallsService handles communication with the firewall related methods of the Xelon API.
+type FirewallsService service
+
+// FirewallRule represents an inbound firewall rule for a device or a network.
+type FirewallRule struct {
+	Direction  string `json:"direction"`
+	ID         int    `json:"id,omitempty"`
+	LocalVMID  string `json:"localvmid,omitempty"`
+	Name       string `json:"name,omitempty"`
+	NetworkID  int    `json:"network_id,omitempty"`
+	Port       int    `json:"port"`
+	Protocol   string `json:"protocol"`
+	SourceCIDR string `json:"source"`
+}
+
+// FirewallRuleListOptions specifies the optional parameters to the List method.
+type FirewallRuleListOptions struct {
+	LocalVMID string
+	NetworkID int
+}
+
+// List provides a list of firewall rules in the tenant, optionally filtered by device or network.
+func (s *FirewallsService) List(tenantID string, opts *FirewallRuleListOptions) ([]FirewallRule, *http.Response, error) {
+	if tenantID == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+
+	params := url.Values{}
+	params.Set("tenant", tenantID)
+	if opts != nil && opts.LocalVMID != "" {
+		params.Set("localvmid", opts.LocalVMID)
+	}
+	if opts != nil && opts.NetworkID != 0 {
+		params.Set("network_id", strconv.Itoa(opts.NetworkID))
+	}
+	path := fmt.Sprintf("%v?%v", firewallBasePath, params.Encode())
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rules []FirewallRule
+	resp, err := s.client.Do(context.Background(), req, &rules)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return rules, resp, nil
+}
+
+// Create makes a new firewall rule for a device or a network.
+func (s *FirewallsService) Create(rule *FirewallRule) (*FirewallRule, *http.Response, error) {
+	if rule == nil {
+		return nil, nil, ErrEmptyPayloadNotAllowed
+	}
+	if rule.LocalVMID == "" && rule.NetworkID == 0 {
+		return nil, nil, ErrEmptyArgument
+	}
+
+	path := fmt.Sprintf("%v/create", firewallBasePath)
+
+	req, err := s.client.NewRequest(http.MethodPost, path, rule)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	createdRule := new(FirewallRule)
+	resp, err := s.client.Do(context.Background(), req, createdRule)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return createdRule, resp, nil
+}
+
+// Delete removes a firewall rule.
+func (s *FirewallsService) Delete(ruleID int) (*http.Response, error) {
+	if ruleID == 0 {
+		return nil, ErrEmptyArgument
+	}
+
+	path := fmt.Sprintf("%v/%v", firewallBasePath, ruleID)
+
+	req, err := s.client.NewRequest(http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(context.Background(), req, nil)
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFirewallsService_List_emptyTenantID(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, _, err := client.Firewalls.List("", nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestFirewallsService_List(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/firewalls", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "tenantID", r.URL.Query().Get("tenant"))
+		assert.Equal(t, "localVMID", r.URL.Query().Get("localvmid"))
+		assert.Empty(t, r.URL.Query().Get("network_id"))
+		_, _ = fmt.Fprint(w, `[{"id":1,"direction":"inbound","localvmid":"localVMID","port":22,"protocol":"tcp","source":"0.0.0.0/0"}]`)
+	})
+
+	rules, _, err := client.Firewalls.List("tenantID", &FirewallRuleListOptions{LocalVMID: "localVMID"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []FirewallRule{{
+		Direction:  FirewallDirectionInbound,
+		ID:         1,
+		LocalVMID:  "localVMID",
+		Port:       22,
+		Protocol:   FirewallProtocolTCP,
+		SourceCIDR: "0.0.0.0/0",
+	}}, rules)
+}
+
+func TestFirewallsService_Create_emptyFirewallRule(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, _, err := client.Firewalls.Create(nil)
+	assert.Equal(t, ErrEmptyPayloadNotAllowed, err)
+
+	_, _, err = client.Firewalls.Create(&FirewallRule{Port: 22})
+	assert.Equal(t, ErrEmptyArgument, err)
+}
+
+func TestFirewallsService_Create(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/firewalls/create", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		rule := new(FirewallRule)
+		_ = json.NewDecoder(r.Body).Decode(rule)
+		assert.Equal(t, 2376, rule.Port)
+		_, _ = fmt.Fprint(w, `{"id":2,"port":2376}`)
+	})
+
+	rule, _, err := client.Firewalls.Create(&FirewallRule{LocalVMID: "localVMID", Port: 2376})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, rule.ID)
+}
+
+func TestFirewallsService_Delete_emptyRuleID(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.Firewalls.Delete(0)
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const hypervisorBasePath = "hypervisors"
+
+// HypervisorsService handles communication with the hypervisor related methods of the Xelon API.
+type HypervisorsService service
+
+// HypervisorSystem represents a hypervisor system on which devices are placed.
+type HypervisorSystem struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// List provides a list of hypervisor systems on which devices of the tenant can be placed.
+func (s *HypervisorsService) List(tenantID string) ([]HypervisorSystem, *http.Response, error) {
+	if tenantID == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+
+	params := url.Values{}
+	params.Set("tenant", tenantID)
+	path := fmt.Sprintf("%v?%v", hypervisorBasePath, params.Encode())
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var systems []HypervisorSystem
+	resp, err := s.client.Do(context.Background(), req, &systems)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return systems, resp, nil
+}
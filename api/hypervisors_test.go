@@ -0,0 +1,34 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHypervisorsService_List_emptyTenantID(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, _, err := client.Hypervisors.List("")
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestHypervisorsService_List(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/hypervisors", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "tenantID", r.URL.Query().Get("tenant"))
+		_, _ = fmt.Fprint(w, `[{"id":1,"name":"hv-zrh-1"},{"id":2,"name":"hv-zrh-2"}]`)
+	})
+
+	systems, _, err := client.Hypervisors.List("tenantID")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []HypervisorSystem{{ID: 1, Name: "hv-zrh-1"}, {ID: 2, Name: "hv-zrh-2"}}, systems)
+}
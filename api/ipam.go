@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const networkBasePath = "networks"
+
+// IPAMService handles communication with the IP address management related methods of the Xelon API.
+type IPAMService service
+
+// IPAddress represents an IP address of a network.
+type IPAddress struct {
+	Address   string `json:"ip"`
+	LocalVMID string `json:"localvmid,omitempty"`
+	NetworkID int    `json:"network_id,omitempty"`
+	Reserved  bool   `json:"reserved,omitempty"`
+}
+
+type ipAddressRequest struct {
+	Address string `json:"ip"`
+}
+
+// ListFree provides a list of IP addresses of the network which are neither assigned nor reserved.
+func (s *IPAMService) ListFree(networkID int) ([]IPAddress, *http.Response, error) {
+	if networkID == 0 {
+		return nil, nil, ErrEmptyArgument
+	}
+
+	params := url.Values{}
+	params.Set("free", "true")
+	path := fmt.Sprintf("%v/%v/ips?%v", networkBasePath, networkID, params.Encode())
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var addresses []IPAddress
+	resp, err := s.client.Do(context.Background(), req, &addresses)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return addresses, resp, nil
+}
+
+// Get provides the state of an IP address of the network, i.e. whether it is reserved and the device which
+// it is assigned to.
+func (s *IPAMService) Get(networkID int, address string) (*IPAddress, *http.Response, error) {
+	if networkID == 0 || address == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+
+	path := fmt.Sprintf("%v/%v/ips/%v", networkBasePath, networkID, url.PathEscape(address))
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ipAddress := new(IPAddress)
+	resp, err := s.client.Do(context.Background(), req, ipAddress)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return ipAddress, resp, nil
+}
+
+// Reserve reserves an IP address of the network, so it is only assigned to devices which request it.
+func (s *IPAMService) Reserve(networkID int, address string) (*IPAddress, *http.Response, error) {
+	if networkID == 0 || address == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+
+	path := fmt.Sprintf("%v/%v/ips/reserve", networkBasePath, networkID)
+
+	req, err := s.client.NewRequest(http.MethodPost, path, &ipAddressRequest{Address: address})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reserved := new(IPAddress)
+	resp, err := s.client.Do(context.Background(), req, reserved)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return reserved, resp, nil
+}
+
+// Release releases a reserved IP address of the network.
+func (s *IPAMService) Release(networkID int, address string) (*http.Response, error) {
+	if networkID == 0 || address == "" {
+		return nil, ErrEmptyArgument
+	}
+
+	path := fmt.Sprintf("%v/%v/ips/release", networkBasePath, networkID)
+
+	req, err := s.client.NewRequest(http.MethodPost, path, &ipAddressRequest{Address: address})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(context.Background(), req, nil)
+}
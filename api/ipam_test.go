@@ -0,0 +1,115 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPAMService_ListFree_emptyNetworkID(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, _, err := client.IPAM.ListFree(0)
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestIPAMService_ListFree(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/networks/7/ips", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "true", r.URL.Query().Get("free"))
+		_, _ = fmt.Fprint(w, `[{"ip":"10.0.0.20","network_id":7},{"ip":"10.0.0.21","network_id":7}]`)
+	})
+
+	addresses, _, err := client.IPAM.ListFree(7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []IPAddress{
+		{Address: "10.0.0.20", NetworkID: 7},
+		{Address: "10.0.0.21", NetworkID: 7},
+	}, addresses)
+}
+
+func TestIPAMService_Get_emptyArguments(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, _, err := client.IPAM.Get(0, "10.0.0.20")
+	assert.Equal(t, ErrEmptyArgument, err)
+
+	_, _, err = client.IPAM.Get(7, "")
+	assert.Equal(t, ErrEmptyArgument, err)
+}
+
+func TestIPAMService_Get(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/networks/7/ips/10.0.0.20", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		_, _ = fmt.Fprint(w, `{"ip":"10.0.0.20","localvmid":"abc","network_id":7,"reserved":true}`)
+	})
+
+	address, _, err := client.IPAM.Get(7, "10.0.0.20")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &IPAddress{Address: "10.0.0.20", LocalVMID: "abc", NetworkID: 7, Reserved: true}, address)
+}
+
+func TestIPAMService_Reserve_emptyArguments(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, _, err := client.IPAM.Reserve(0, "10.0.0.20")
+	assert.Equal(t, ErrEmptyArgument, err)
+
+	_, _, err = client.IPAM.Reserve(7, "")
+	assert.Equal(t, ErrEmptyArgument, err)
+}
+
+func TestIPAMService_Reserve(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/networks/7/ips/reserve", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		request := new(ipAddressRequest)
+		_ = json.NewDecoder(r.Body).Decode(request)
+		assert.Equal(t, "10.0.0.20", request.Address)
+		_, _ = fmt.Fprint(w, `{"ip":"10.0.0.20","network_id":7,"reserved":true}`)
+	})
+
+	address, _, err := client.IPAM.Reserve(7, "10.0.0.20")
+
+	assert.NoError(t, err)
+	assert.True(t, address.Reserved)
+}
+
+func TestIPAMService_Release_emptyArguments(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.IPAM.Release(7, "")
+
+	assert.Equal(t, ErrEmptyArgument, err)
+}
+
+func TestIPAMService_Release(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/networks/7/ips/release", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		request := new(ipAddressRequest)
+		_ = json.NewDecoder(r.Body).Decode(request)
+		assert.Equal(t, "10.0.0.20", request.Address)
+	})
+
+	_, err := client.IPAM.Release(7, "10.0.0.20")
+
+	assert.NoError(t, err)
+}
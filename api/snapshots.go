@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const snapshotBasePath = "snapshots"
+
+// SnapshotsService handles communication with the snapshot related methods of the Xelon API.
+type SnapshotsService service
+
+// Snapshot represents a snapshot of a Xelon device.
+type Snapshot struct {
+	CreatedAt string `json:"created_at,omitempty"`
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+}
+
+type SnapshotCreateRequest struct {
+	Name string `json:"name"`
+}
+
+// List provides a list of all snapshots of device with specific localvmid.
+func (s *SnapshotsService) List(localVMID string) ([]Snapshot, *http.Response, error) {
+	if localVMID == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+
+	path := fmt.Sprintf("%v/%v/%v", deviceBasePath, localVMID, snapshotBasePath)
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var snapshots []Snapshot
+	resp, err := s.client.Do(context.Background(), req, &snapshots)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return snapshots, resp, nil
+}
+
+// Create makes a new snapshot of device with specific localvmid.
+func (s *SnapshotsService) Create(localVMID string, snapshotCreateRequest *SnapshotCreateRequest) (*Snapshot, *http.Response, error) {
+	if localVMID == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+	if snapshotCreateRequest == nil {
+		return nil, nil, ErrEmptyPayloadNotAllowed
+	}
+
+	path := fmt.Sprintf("%v/%v/%v/create", deviceBasePath, localVMID, snapshotBasePath)
+
+	req, err := s.client.NewRequest(http.MethodPost, path, snapshotCreateRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	snapshot := new(Snapshot)
+	resp, err := s.client.Do(context.Background(), req, snapshot)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return snapshot, resp, nil
+}
+
+// Restore reverts device with specific localvmid to the snapshot.
+func (s *SnapshotsService) Restore(localVMID string, snapshotID int) (*http.Response, error) {
+	if localVMID == "" || snapshotID == 0 {
+		return nil, ErrEmptyArgument
+	}
+
+	path := fmt.Sprintf("%v/%v/%v/%v/restore", deviceBasePath, localVMID, snapshotBasePath, snapshotID)
+
+	req, err := s.client.NewRequest(http.MethodPost, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(context.Background(), req, nil)
+}
+
+// Delete removes the snapshot of device with specific localvmid.
+func (s *SnapshotsService) Delete(localVMID string, snapshotID int) (*http.Response, error) {
+	if localVMID == "" || snapshotID == 0 {
+		return nil, ErrEmptyArgument
+	}
+
+	path := fmt.Sprintf("%v/%v/%v/%v", deviceBasePath, localVMID, snapshotBasePath, snapshotID)
+
+	req, err := s.client.NewRequest(http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(context.Background(), req, nil)
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotsService_List_emptyLocalVMID(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, _, err := client.Snapshots.List("")
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestSnapshotsService_List(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/vmlist/localVMID/snapshots", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		_, _ = fmt.Fprint(w, `[{"id":1,"name":"before-upgrade","created_at":"2020-01-01 10:00:00"}]`)
+	})
+
+	snapshots, _, err := client.Snapshots.List("localVMID")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Snapshot{{ID: 1, Name: "before-upgrade", CreatedAt: "2020-01-01 10:00:00"}}, snapshots)
+}
+
+func TestSnapshotsService_Create_emptyLocalVMID(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, _, err := client.Snapshots.Create("", nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestSnapshotsService_Create_emptySnapshotCreateRequest(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, _, err := client.Snapshots.Create("localVMID", nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyPayloadNotAllowed.Error(), err.Error())
+}
+
+func TestSnapshotsService_Create(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/vmlist/localVMID/snapshots/create", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		request := new(SnapshotCreateRequest)
+		_ = json.NewDecoder(r.Body).Decode(request)
+		assert.Equal(t, "before-upgrade", request.Name)
+		_, _ = fmt.Fprint(w, `{"id":1,"name":"before-upgrade"}`)
+	})
+
+	snapshot, _, err := client.Snapshots.Create("localVMID", &SnapshotCreateRequest{Name: "before-upgrade"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Snapshot{ID: 1, Name: "before-upgrade"}, snapshot)
+}
+
+func TestSnapshotsService_Restore_emptyArguments(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.Snapshots.Restore("", 1)
+	assert.Equal(t, ErrEmptyArgument, err)
+
+	_, err = client.Snapshots.Restore("localVMID", 0)
+	assert.Equal(t, ErrEmptyArgument, err)
+}
+
+func TestSnapshotsService_Delete_emptyArguments(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.Snapshots.Delete("", 1)
+	assert.Equal(t, ErrEmptyArgument, err)
+
+	_, err = client.Snapshots.Delete("localVMID", 0)
+	assert.Equal(t, ErrEmptyArgument, err)
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const templateBasePath = "templates"
+
+// TemplatesService handles communication with the template related methods of the Xelon API.
+type TemplatesService service
+
+// Template represents a template from which Xelon devices are created.
+type Template struct {
+	CreatedAt string `json:"created_at,omitempty"`
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+}
+
+type TemplateCreateRequest struct {
+	Name string `json:"name"`
+}
+
+// List provides a list of all templates which are available in the tenant, including custom templates.
+func (s *TemplatesService) List(tenantID string) ([]Template, *http.Response, error) {
+	if tenantID == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+
+	params := url.Values{}
+	params.Set("tenant", tenantID)
+	path := fmt.Sprintf("%v?%v", templateBasePath, params.Encode())
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var templates []Template
+	resp, err := s.client.Do(context.Background(), req, &templates)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return templates, resp, nil
+}
+
+// CreateFromDevice converts device with specific localvmid into a custom template. The device must be stopped.
+func (s *TemplatesService) CreateFromDevice(localVMID string, templateCreateRequest *TemplateCreateRequest) (*Template, *http.Response, error) {
+	if localVMID == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+	if templateCreateRequest == nil {
+		return nil, nil, ErrEmptyPayloadNotAllowed
+	}
+
+	path := fmt.Sprintf("%v/%v/template", deviceBasePath, localVMID)
+
+	req, err := s.client.NewRequest(http.MethodPost, path, templateCreateRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := new(Template)
+	resp, err := s.client.Do(context.Background(), req, template)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return template, resp, nil
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplatesService_List_emptyTenantID(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, _, err := client.Templates.List("")
+
+	assert.Equal(t, ErrEmptyArgument, err)
+}
+
+func TestTemplatesService_List(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/templates", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "tenantID", r.URL.Query().Get("tenant"))
+		_, _ = fmt.Fprint(w, `[{"id":7,"name":"docker-golden","created_at":"2026-10-01 10:00:00"}]`)
+	})
+
+	templates, _, err := client.Templates.List("tenantID")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Template{{ID: 7, Name: "docker-golden", CreatedAt: "2026-10-01 10:00:00"}}, templates)
+}
+
+func TestTemplatesService_List_escapesTenantID(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/templates", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "tenant&id=1", r.URL.Query().Get("tenant"))
+		assert.Empty(t, r.URL.Query().Get("id"))
+		_, _ = fmt.Fprint(w, `[]`)
+	})
+
+	_, _, err := client.Templates.List("tenant&id=1")
+
+	assert.NoError(t, err)
+}
+
+func TestTemplatesService_CreateFromDevice_emptyArguments(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, _, err := client.Templates.CreateFromDevice("", &TemplateCreateRequest{})
+	assert.Equal(t, ErrEmptyArgument, err)
+
+	_, _, err = client.Templates.CreateFromDevice("localVMID", nil)
+	assert.Equal(t, ErrEmptyPayloadNotAllowed, err)
+}
+
+func TestTemplatesService_CreateFromDevice(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/vmlist/localVMID/template", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		request := new(TemplateCreateRequest)
+		_ = json.NewDecoder(r.Body).Decode(request)
+		assert.Equal(t, &TemplateCreateRequest{Name: "docker-golden"}, request)
+		_, _ = fmt.Fprint(w, `{"id":7,"name":"docker-golden"}`)
+	})
+
+	template, _, err := client.Templates.CreateFromDevice("localVMID", &TemplateCreateRequest{Name: "docker-golden"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Template{ID: 7, Name: "docker-golden"}, template)
+}
@@ -15,6 +15,23 @@ type Tenant struct {
 	TenantIdentifier string `json:"tenant_identifier"`
 }
 
+// TenantLimits represents the allowed ranges of device parameters and the quotas of a tenant.
+type TenantLimits struct {
+	CPUCores     ResourceLimit `json:"cpu"`
+	DiskSize     ResourceLimit `json:"disk"`
+	Memory       ResourceLimit `json:"ram"`
+	SwapDiskSize ResourceLimit `json:"swap"`
+}
+
+// ResourceLimit represents the allowed range of a resource for a single device (Min and Max)
+// and the tenant-wide quota of the resource. Quota is zero if the resource is not limited.
+type ResourceLimit struct {
+	Min   int `json:"min"`
+	Max   int `json:"max"`
+	Quota int `json:"quota"`
+	Used  int `json:"used"`
+}
+
 // Get provides information about user especially tenant id.
 func (s *TenantService) Get() (*Tenant, *http.Response, error) {
 	path := fmt.Sprintf("%s", tenantBasePath)
@@ -32,3 +49,25 @@ func (s *TenantService) Get() (*Tenant, *http.Response, error) {
 
 	return tenant, resp, nil
 }
+
+// GetLimits provides the allowed ranges of device parameters and the quotas for a tenant.
+func (s *TenantService) GetLimits(tenantID string) (*TenantLimits, *http.Response, error) {
+	if tenantID == "" {
+		return nil, nil, ErrEmptyArgument
+	}
+
+	path := fmt.Sprintf("%v/%v/limits", tenantBasePath, tenantID)
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	limits := new(TenantLimits)
+	resp, err := s.client.Do(context.Background(), req, limits)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return limits, resp, nil
+}
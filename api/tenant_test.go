@@ -0,0 +1,34 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantService_GetLimits_emptyTenantID(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	_, _, err := client.Tenant.GetLimits("")
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrEmptyArgument.Error(), err.Error())
+}
+
+func TestTenantService_GetLimits(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/tenant/tenantID/limits", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		_, _ = fmt.Fprint(w, `{"cpu":{"min":1,"max":8,"quota":32,"used":10},"ram":{"min":1,"max":64}}`)
+	})
+
+	limits, _, err := client.Tenant.GetLimits("tenantID")
+
+	assert.NoError(t, err)
+	assert.Equal(t, ResourceLimit{Min: 1, Max: 8, Quota: 32, Used: 10}, limits.CPUCores)
+	assert.Equal(t, ResourceLimit{Min: 1, Max: 64}, limits.Memory)
+}
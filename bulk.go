@@ -0,0 +1,202 @@
+package xelon
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/docker/machine/libmachine/auth"
+	"github.com/docker/machine/libmachine/cert"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/engine"
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/swarm"
+	"github.com/docker/machine/libmachine/version"
+)
+
+// provisionMachine installs and configures the Docker engine of a machine in the store like docker-machine
+// create does, by running docker-machine provision. It is replaced in tests.
+var provisionMachine = func(storePath, machineName string) error {
+	output, err := exec.Command("docker-machine", "--storage-path", storePath, "provision", machineName).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("could not provision machine %v: %v: %v", machineName, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+var namePatternRegexp = regexp.MustCompile(`^[^%]*%[0-9]*d[^%]*$`)
+
+// BulkCreateResult represents the outcome of creating one machine with BulkCreate.
+type BulkCreateResult struct {
+	Err         error
+	LocalVMID   string
+	MachineName string
+	RolledBack  bool
+}
+
+// BulkNames returns count machine names from pattern, which contains a single %d verb, e.g. "swarm-%02d",
+// numbered from start.
+func BulkNames(pattern string, start, count int) ([]string, error) {
+	if !namePatternRegexp.MatchString(pattern) {
+		return nil, fmt.Errorf("invalid name pattern %q, expected a single %%d verb, e.g. swarm-%%02d", pattern)
+	}
+	if count < 1 {
+		return nil, fmt.Errorf("count must be at least 1")
+	}
+
+	names := make([]string, count)
+	seen := make(map[string]bool, count)
+	for i := range names {
+		names[i] = fmt.Sprintf(pattern, start+i)
+		if err := validateHostname(names[i]); err != nil {
+			return nil, err
+		}
+		if seen[names[i]] {
+			return nil, fmt.Errorf("name pattern %q yields %v more than once", pattern, names[i])
+		}
+		seen[names[i]] = true
+	}
+	return names, nil
+}
+
+// BulkCreate creates a machine in the docker-machine store at storePath for every name, with at most parallel
+// machines being created at the same time. configure sets the options of the driver of each machine. Every
+// machine is saved in the store with the default options of docker-machine create and provisioned with
+// docker-machine provision, which must be in the PATH together with the driver.
+//
+// If rollback is set and a machine cannot be created, all machines are removed again, including the machines
+// created successfully. Otherwise machines which cannot be created are left in the store like with
+// docker-machine create.
+func BulkCreate(storePath string, names []string, parallel int, rollback bool, configure func(*Driver) error) ([]BulkCreateResult, error) {
+	if parallel < 1 {
+		return nil, fmt.Errorf("parallel must be at least 1")
+	}
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(storePath, "machines", name)); err == nil {
+			return nil, fmt.Errorf("machine %v already exists", name)
+		}
+	}
+	// the CA and client certificates are shared by all machines and must not be created concurrently
+	if err := cert.BootstrapCertificates(newHostOptions(storePath, "").AuthOptions); err != nil {
+		return nil, fmt.Errorf("could not generate certificates: %v", err)
+	}
+
+	results := make([]BulkCreateResult, len(names))
+	placements := newPlacements()
+	saved := make([]*Driver, len(names))
+	semaphore := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			results[i].MachineName = name
+			d := NewDriver(name, storePath)
+			if err := configure(d); err != nil {
+				results[i].Err = err
+				return
+			}
+			d.placements = placements
+			if d.DryRun {
+				// a dry run doesn't create a machine which could be saved, provisioned or rolled back
+				results[i].Err = fmt.Errorf("xelon-dry-run cannot be used to create machines in bulk")
+				return
+			}
+			if err := d.PreCreateCheck(); err != nil {
+				results[i].Err = err
+				return
+			}
+
+			config := &hostConfig{
+				ConfigVersion: version.ConfigVersion,
+				Driver:        d,
+				DriverName:    d.DriverName(),
+				HostOptions:   newHostOptions(storePath, name),
+				Name:          name,
+			}
+			// like docker-machine create, the machine is saved before the device is created, so it can be
+			// removed if the create fails
+			if err := saveHost(storePath, config); err != nil {
+				results[i].Err = err
+				return
+			}
+			saved[i] = d
+
+			log.Infof("Creating machine %v...", name)
+			results[i].Err = d.Create()
+			results[i].LocalVMID = d.LocalVMID
+			if err := saveHost(storePath, config); err != nil && results[i].Err == nil {
+				results[i].Err = err
+			}
+			if results[i].Err == nil {
+				log.Infof("Provisioning machine %v...", name)
+				results[i].Err = provisionMachine(storePath, name)
+			}
+		}(i, name)
+	}
+	wg.Wait()
+
+	if !rollback || !hasBulkCreateError(results) {
+		return results, nil
+	}
+	for i, d := range saved {
+		if d == nil {
+			continue
+		}
+		log.Infof("Rolling back machine %v...", d.MachineName)
+		if err := d.Remove(); err != nil {
+			log.Warnf("Could not remove machine %v: %v", d.MachineName, err)
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(storePath, "machines", d.MachineName)); err != nil {
+			log.Warnf("Could not remove machine %v from the store: %v", d.MachineName, err)
+			continue
+		}
+		results[i].RolledBack = true
+	}
+	return results, nil
+}
+
+func hasBulkCreateError(results []BulkCreateResult) bool {
+	for _, result := range results {
+		if result.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// newHostOptions returns the host options of docker-machine create with default values for the machine with
+// the given name.
+func newHostOptions(storePath, name string) *hostOptions {
+	certsDir := filepath.Join(storePath, "certs")
+	machineDir := filepath.Join(storePath, "machines", name)
+	return &hostOptions{
+		AuthOptions: &auth.Options{
+			CertDir:          certsDir,
+			CaCertPath:       filepath.Join(certsDir, "ca.pem"),
+			CaPrivateKeyPath: filepath.Join(certsDir, "ca-key.pem"),
+			ClientCertPath:   filepath.Join(certsDir, "cert.pem"),
+			ClientKeyPath:    filepath.Join(certsDir, "key.pem"),
+			ServerCertPath:   filepath.Join(machineDir, "server.pem"),
+			ServerKeyPath:    filepath.Join(machineDir, "server-key.pem"),
+			StorePath:        machineDir,
+		},
+		EngineOptions: &engine.Options{
+			InstallURL: drivers.DefaultEngineInstallURL,
+			TLSVerify:  true,
+		},
+		SwarmOptions: &swarm.Options{
+			Host:     "tcp://0.0.0.0:3376",
+			Image:    "swarm:latest",
+			Strategy: "spread",
+		},
+	}
+}
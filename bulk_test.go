@@ -0,0 +1,156 @@
+package xelon
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkNames(t *testing.T) {
+	names, err := BulkNames("swarm-%02d", 9, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"swarm-09", "swarm-10", "swarm-11"}, names)
+}
+
+func TestBulkNames_invalid(t *testing.T) {
+	for _, pattern := range []string{"swarm", "swarm-%s", "swarm-%d-%d", "swarm_%d"} {
+		_, err := BulkNames(pattern, 1, 3)
+		assert.Error(t, err, pattern)
+	}
+	_, err := BulkNames("swarm-%d", 1, 0)
+	assert.Error(t, err)
+}
+
+func setupBulkCreate() (storePath string, mux *http.ServeMux, configure func(*Driver) error, teardown func()) {
+	driver, mux, teardown := setup("swarm-1")
+	configure = func(d *Driver) error {
+		d.APIBaseURL = driver.APIBaseURL
+		d.AllowedSourceCIDR = driver.AllowedSourceCIDR
+		d.CPUCores = driver.CPUCores
+		d.DevicePassword = driver.DevicePassword
+		d.DevicePasswordMinCharacterClasses = driver.DevicePasswordMinCharacterClasses
+		d.DevicePasswordMinLength = driver.DevicePasswordMinLength
+		d.DiskSize = driver.DiskSize
+		d.Memory = driver.Memory
+		d.SwapDiskSize = driver.SwapDiskSize
+		d.Token = driver.Token
+		return nil
+	}
+	return driver.StorePath, mux, configure, teardown
+}
+
+func TestBulkCreate(t *testing.T) {
+	storePath, _, configure, teardown := setupBulkCreate()
+	defer teardown()
+	var mu sync.Mutex
+	var provisioned []string
+	defer func(original func(string, string) error) { provisionMachine = original }(provisionMachine)
+	provisionMachine = func(storePath, machineName string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		provisioned = append(provisioned, machineName)
+		return nil
+	}
+
+	results, err := BulkCreate(storePath, []string{"swarm-2", "swarm-3"}, 2, false, configure)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []BulkCreateResult{
+		{LocalVMID: "localVMID", MachineName: "swarm-2"},
+		{LocalVMID: "localVMID", MachineName: "swarm-3"},
+	}, results)
+	assert.ElementsMatch(t, []string{"swarm-2", "swarm-3"}, provisioned)
+	assert.FileExists(t, filepath.Join(storePath, "certs", "ca.pem"))
+
+	data, err := ioutil.ReadFile(filepath.Join(storePath, "machines", "swarm-2", "config.json"))
+	assert.NoError(t, err)
+	config := struct {
+		DriverName  string
+		HostOptions struct {
+			AuthOptions struct {
+				ServerCertPath string
+				StorePath      string
+			}
+		}
+		Name string
+	}{}
+	assert.NoError(t, json.Unmarshal(data, &config))
+	assert.Equal(t, "xelon", config.DriverName)
+	assert.Equal(t, "swarm-2", config.Name)
+	assert.Equal(t, filepath.Join(storePath, "machines", "swarm-2"), config.HostOptions.AuthOptions.StorePath)
+	assert.Equal(t, filepath.Join(storePath, "machines", "swarm-2", "server.pem"), config.HostOptions.AuthOptions.ServerCertPath)
+	d, err := LoadDriver(storePath, "swarm-2")
+	assert.NoError(t, err)
+	assert.Equal(t, "localVMID", d.LocalVMID)
+}
+
+func TestBulkCreate_rollback(t *testing.T) {
+	storePath, mux, configure, teardown := setupBulkCreate()
+	defer teardown()
+	defer func(original func(string, string) error) { provisionMachine = original }(provisionMachine)
+	provisionMachine = func(storePath, machineName string) error {
+		if machineName == "swarm-3" {
+			return fmt.Errorf("could not provision machine %v", machineName)
+		}
+		return nil
+	}
+	var mu sync.Mutex
+	deleted := 0
+	mux.HandleFunc("/vmlist/localVMID", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, http.MethodDelete, r.Method)
+		deleted++
+	})
+
+	results, err := BulkCreate(storePath, []string{"swarm-2", "swarm-3"}, 1, true, configure)
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.NoError(t, results[0].Err)
+	assert.Error(t, results[1].Err)
+	assert.True(t, results[0].RolledBack)
+	assert.True(t, results[1].RolledBack)
+	assert.Equal(t, 2, deleted)
+	for _, name := range []string{"swarm-2", "swarm-3"} {
+		_, err := os.Stat(filepath.Join(storePath, "machines", name))
+		assert.True(t, os.IsNotExist(err), name)
+	}
+}
+
+func TestBulkCreate_existingMachine(t *testing.T) {
+	storePath, _, configure, teardown := setupBulkCreate()
+	defer teardown()
+
+	_, err := BulkCreate(storePath, []string{"swarm-1"}, 1, false, configure)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+}
+
+func TestBulkCreate_dryRun(t *testing.T) {
+	storePath, mux, configure, teardown := setupBulkCreate()
+	defer teardown()
+	mux.HandleFunc("/vmlist/create", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected create of a device")
+	})
+
+	results, err := BulkCreate(storePath, []string{"swarm-2"}, 1, true, func(d *Driver) error {
+		d.DryRun = true
+		return configure(d)
+	})
+
+	assert.NoError(t, err)
+	assert.Error(t, results[0].Err)
+	assert.False(t, results[0].RolledBack)
+	_, err = os.Stat(filepath.Join(storePath, "machines", "swarm-2"))
+	assert.True(t, os.IsNotExist(err), "machine of a dry run must not be saved")
+}
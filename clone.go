@@ -0,0 +1,76 @@
+package xelon
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/docker/machine/libmachine/log"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon/api"
+)
+
+// resolveCloneSource determines whether xelon-clone-from names a template, by its name or ID, or a device by
+// its localvmid. The device is created from a template as usual, while a device is cloned.
+func (d *Driver) resolveCloneSource(client *api.Client) error {
+	templates, _, err := client.Templates.List(d.TenantID)
+	if err != nil {
+		return fmt.Errorf("could not list templates: %v", err)
+	}
+	for _, template := range templates {
+		if template.Name == d.CloneFrom || strconv.Itoa(template.ID) == d.CloneFrom {
+			log.Infof("Creating Xelon device from template %v (%d)", template.Name, template.ID)
+			d.TemplateID = template.ID
+			return nil
+		}
+	}
+
+	if _, resp, err := client.Devices.Get(d.TenantID, d.CloneFrom); err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("xelon-clone-from %v is neither a template nor a device", d.CloneFrom)
+		}
+		return fmt.Errorf("could not get device %v to clone: %v", d.CloneFrom, err)
+	}
+	d.cloneSourceVMID = d.CloneFrom
+	return nil
+}
+
+// cloneDevice creates the device of the machine as a copy of the source device with the given tags. The
+// hostname and network are set for the copy, the SSH key is added once it is provisioned.
+func (d *Driver) cloneDevice(tags map[string]string) (*api.DeviceCreateResponse, error) {
+	log.Infof("Cloning Xelon device %v...", d.cloneSourceVMID)
+	client, err := d.getClient()
+	if err != nil {
+		return nil, err
+	}
+	deviceCreateResponse, _, err := client.Devices.Clone(d.cloneSourceVMID, &api.DeviceCloneRequest{
+		DisplayName: d.MachineName,
+		Hostname:    d.MachineName,
+		HVSystemID:  d.HVSystemID,
+		NetworkID:   d.NetworkID,
+		Tags:        tags,
+	})
+	return deviceCreateResponse, err
+}
+
+// adoptDeviceResources takes over the resources of a device which was not created with the options of the
+// machine, i.e. a clone or a pool device, and warns if they differ from the options.
+func (d *Driver) adoptDeviceResources(client *api.Client) error {
+	deviceRoot, _, err := client.Devices.Get(d.TenantID, d.LocalVMID)
+	if err != nil {
+		return err
+	}
+	device := deviceRoot.Device
+	if device.CPU != d.CPUCores || device.RAM != d.Memory || (device.DiskSize != 0 && device.DiskSize != d.DiskSize) {
+		log.Warnf("Xelon device has %d CPU cores, %d GB memory and %d GB disk instead of %d CPU cores, %d GB memory and %d GB disk",
+			device.CPU, device.RAM, device.DiskSize, d.CPUCores, d.Memory, d.DiskSize)
+	}
+	d.CPUCores = device.CPU
+	d.Memory = device.RAM
+	if device.DiskSize != 0 {
+		d.DiskSize = device.DiskSize
+	}
+	// the password of the device was not set by the machine and is not known
+	d.DevicePassword = ""
+	return nil
+}
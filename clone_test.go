@@ -0,0 +1,119 @@
+package xelon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupClone(t *testing.T) (driver *Driver, mux *http.ServeMux, teardown func()) {
+	driver, mux, teardown = setup("ci-runner-1")
+	mux.HandleFunc("/templates", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[{"id":7,"name":"docker-golden"}]`)
+	})
+	return driver, mux, teardown
+}
+
+func TestDriver_Create_CloneFromDevice(t *testing.T) {
+	driver, mux, teardown := setupClone(t)
+	defer teardown()
+	driver.CloneFrom = "golden"
+	driver.CPUCores = 4
+	var clone map[string]interface{}
+	mux.HandleFunc("/vmlist/golden/clone", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		_ = json.NewDecoder(r.Body).Decode(&clone)
+		_, _ = fmt.Fprint(w, `{"device":{"localvmid":"localVMID"}}`)
+	})
+	mux.HandleFunc("/vmlist/create", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected create of a device")
+	})
+
+	err := driver.Create()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "localVMID", driver.LocalVMID)
+	assert.Equal(t, "ci-runner-1", clone["hostname"])
+	assert.Equal(t, "ci-runner-1", clone["tags"].(map[string]interface{})[tagMachineName])
+	assert.NotContains(t, clone, "password")
+	assert.Equal(t, 2, driver.CPUCores)
+	assert.Empty(t, driver.DevicePassword)
+	assert.FileExists(t, driver.GetSSHKeyPath())
+}
+
+func TestDriver_Create_CloneFromTemplate(t *testing.T) {
+	for _, cloneFrom := range []string{"docker-golden", "7"} {
+		t.Run(cloneFrom, func(t *testing.T) {
+			driver, mux, teardown := setupClone(t)
+			defer teardown()
+			driver.CloneFrom = cloneFrom
+			templateID := ""
+			mux.HandleFunc("/vmlist/create", func(w http.ResponseWriter, r *http.Request) {
+				templateID = r.URL.Query().Get("template_id")
+				_, _ = fmt.Fprint(w, `{"device":{"localvmid":"localVMID"}}`)
+			})
+
+			err := driver.Create()
+
+			assert.NoError(t, err)
+			assert.Equal(t, "7", templateID)
+			assert.Equal(t, 7, driver.TemplateID)
+		})
+	}
+}
+
+func TestDriver_Create_CloneFromUnknown(t *testing.T) {
+	driver, mux, teardown := setupClone(t)
+	defer teardown()
+	driver.CloneFrom = "unknown"
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+
+	err := driver.Create()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "neither a template nor a device")
+	assert.Empty(t, driver.LocalVMID)
+}
+
+func TestDriver_SetConfigFromFlags_CloneFromWithProfileTemplate(t *testing.T) {
+	teardown := useConfig(t, testConfig)
+	defer teardown()
+	driver := NewDriver("default", "path")
+	flags := &drivers.CheckDriverOptions{
+		FlagsValues: map[string]interface{}{
+			"xelon-clone-from": "docker-golden",
+			"xelon-profile":    "ci",
+		},
+		CreateFlags: driver.GetCreateFlags(),
+	}
+
+	err := driver.SetConfigFromFlags(flags)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "docker-golden", driver.CloneFrom)
+	assert.Zero(t, driver.TemplateID, "the clone source takes precedence over the template of the profile")
+}
+
+func TestDriver_SetConfigFromFlags_CloneFromWithTemplateID(t *testing.T) {
+	teardown := useConfig(t, "")
+	defer teardown()
+	driver := NewDriver("default", "path")
+	flags := &drivers.CheckDriverOptions{
+		FlagsValues: map[string]interface{}{
+			"xelon-clone-from":  "docker-golden",
+			"xelon-template-id": 12,
+			"xelon-token":       "token",
+		},
+		CreateFlags: driver.GetCreateFlags(),
+	}
+
+	err := driver.SetConfigFromFlags(flags)
+
+	assert.Error(t, err)
+}
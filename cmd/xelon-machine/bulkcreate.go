@@ -0,0 +1,132 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/mcnflag"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon"
+)
+
+func bulkCreate(storagePath string, args []string) error {
+	fs := flag.NewFlagSet("bulk-create", flag.ExitOnError)
+	count := fs.Int("count", 0, "Number of machines to create")
+	parallel := fs.Int("parallel", 5, "Maximum number of machines which are created at the same time")
+	rollback := fs.Bool("rollback", false, "Remove all machines again if a machine cannot be created")
+	start := fs.Int("start", 1, "Number of the first machine")
+	createFlags := xelon.NewDriver("", "").GetCreateFlags()
+	driverOptions := registerDriverFlags(fs, createFlags)
+	args, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	names, err := xelon.BulkNames(args[0], *start, *count)
+	if err != nil {
+		return err
+	}
+	flagsValues, err := driverOptions()
+	if err != nil {
+		return err
+	}
+	if dryRun, _ := flagsValues["xelon-dry-run"].(bool); dryRun {
+		return fmt.Errorf("--xelon-dry-run cannot be used with bulk-create")
+	}
+	options := &drivers.CheckDriverOptions{FlagsValues: flagsValues, CreateFlags: createFlags}
+	results, err := xelon.BulkCreate(storagePath, names, *parallel, *rollback, func(d *xelon.Driver) error {
+		return d.SetConfigFromFlags(options)
+	})
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "MACHINE\tLOCALVMID\tSTATUS")
+	for _, result := range results {
+		status := "created"
+		if result.Err != nil {
+			failed++
+			status = fmt.Sprintf("failed: %v", result.Err)
+		}
+		if result.RolledBack {
+			status += " (rolled back)"
+		}
+		_, _ = fmt.Fprintf(w, "%v\t%v\t%v\n", result.MachineName, result.LocalVMID, status)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d machines could not be created", failed, len(results))
+	}
+	return nil
+}
+
+// registerDriverFlags adds the create flags of the driver to fs. The returned function returns the values of
+// the flags after parsing, with the environment variable of a flag used if it was not given.
+func registerDriverFlags(fs *flag.FlagSet, createFlags []mcnflag.Flag) func() (map[string]interface{}, error) {
+	values := make(map[string]func() interface{}, len(createFlags))
+	envVars := make(map[string]string, len(createFlags))
+	for _, f := range createFlags {
+		switch f := f.(type) {
+		case mcnflag.BoolFlag:
+			value := fs.Bool(f.Name, false, flagUsage(f.Usage, f.EnvVar))
+			values[f.Name] = func() interface{} { return *value }
+			envVars[f.Name] = f.EnvVar
+		case mcnflag.IntFlag:
+			value := fs.Int(f.Name, f.Value, flagUsage(f.Usage, f.EnvVar))
+			values[f.Name] = func() interface{} { return *value }
+			envVars[f.Name] = f.EnvVar
+		case mcnflag.StringFlag:
+			value := fs.String(f.Name, f.Value, flagUsage(f.Usage, f.EnvVar))
+			values[f.Name] = func() interface{} { return *value }
+			envVars[f.Name] = f.EnvVar
+		case mcnflag.StringSliceFlag:
+			value := &stringSlice{}
+			fs.Var(value, f.Name, f.Usage)
+			values[f.Name] = func() interface{} { return []string(*value) }
+		}
+	}
+
+	return func() (map[string]interface{}, error) {
+		given := make(map[string]bool)
+		fs.Visit(func(f *flag.Flag) { given[f.Name] = true })
+		for name, envVar := range envVars {
+			if env := os.Getenv(envVar); !given[name] && env != "" {
+				if err := fs.Set(name, env); err != nil {
+					return nil, fmt.Errorf("invalid value %q of %v: %v", env, envVar, err)
+				}
+			}
+		}
+		flagsValues := make(map[string]interface{}, len(values))
+		for name, value := range values {
+			flagsValues[name] = value()
+		}
+		return flagsValues, nil
+	}
+}
+
+func flagUsage(usage, envVar string) string {
+	if envVar == "" {
+		return usage
+	}
+	return fmt.Sprintf("%v [$%v]", usage, envVar)
+}
+
+// stringSlice is a flag which can be given multiple times.
+type stringSlice []string
+
+func (s *stringSlice) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringSlice) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
@@ -0,0 +1,126 @@
+// Command xelon-machine provides operations for docker-machine hosts created with the xelon driver
+// which are not covered by docker-machine itself.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+type command struct {
+	name        string
+	usage       string
+	description string
+	run         func(storagePath string, args []string) error
+}
+
+var commands = []command{
+	{
+		name:        "bulk-create",
+		usage:       "bulk-create [OPTIONS] PATTERN",
+		description: "Create machines named after a pattern like swarm-%02d in parallel",
+		run:         bulkCreate,
+	},
+	{
+		name:        "orphans",
+		usage:       "orphans [OPTIONS]",
+		description: "List devices without machine in the store and delete them with -confirm",
+		run:         orphans,
+	},
+	{
+		name:        "pool",
+		usage:       "pool -size N [OPTIONS]",
+		description: "Keep N stopped devices in the pool which is used by --xelon-use-pool",
+		run:         pool,
+	},
+	{
+		name:        "protection",
+		usage:       "protection MACHINE on|off",
+		description: "Enable or disable the delete protection of a machine",
+		run:         protection,
+	},
+	{
+		name:        "reap",
+		usage:       "reap [OPTIONS]",
+		description: "Stop or delete devices whose time to live has expired",
+		run:         reap,
+	},
+	{
+		name:        "resize",
+		usage:       "resize [OPTIONS] MACHINE",
+		description: "Change CPU cores and memory of a machine and grow its disk",
+		run:         resize,
+	},
+	{
+		name:        "snapshots",
+		usage:       "snapshots MACHINE",
+		description: "List snapshots of a machine",
+		run:         listSnapshots,
+	},
+	{
+		name:        "snapshot-restore",
+		usage:       "snapshot-restore MACHINE SNAPSHOT",
+		description: "Restore a machine to a named snapshot",
+		run:         restoreSnapshot,
+	},
+}
+
+func main() {
+	flag.Usage = usage
+	storagePath := flag.String("storage-path", defaultStoragePath(), "Configures storage path [$MACHINE_STORAGE_PATH]")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	for _, c := range commands {
+		if c.name == flag.Arg(0) {
+			if err := c.run(*storagePath, flag.Args()[1:]); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", flag.Arg(0))
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	_, _ = fmt.Fprintf(out, "Usage: xelon-machine [OPTIONS] COMMAND [ARGS...]\n\nOptions:\n")
+	flag.PrintDefaults()
+	_, _ = fmt.Fprintf(out, "\nCommands:\n")
+	for _, c := range commands {
+		_, _ = fmt.Fprintf(out, "  %-40s %v\n", c.usage, c.description)
+	}
+}
+
+// defaultStoragePath returns the storage path of docker-machine.
+func defaultStoragePath() string {
+	if path := os.Getenv("MACHINE_STORAGE_PATH"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".docker", "machine")
+}
+
+// parseArgs parses the flags of a command and checks the number of positional arguments.
+func parseArgs(fs *flag.FlagSet, args []string, count int) ([]string, error) {
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() != count {
+		return nil, fmt.Errorf("%v requires %d argument(s), got %d", fs.Name(), count, fs.NArg())
+	}
+	return fs.Args(), nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/machine/libmachine/log"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon"
+)
+
+func orphans(storagePath string, args []string) error {
+	fs := flag.NewFlagSet("orphans", flag.ExitOnError)
+	confirm := fs.Bool("confirm", false, "Delete the orphaned devices")
+	jsonOutput := fs.Bool("json", false, "Print the orphaned devices as JSON")
+	minAge := fs.Duration("min-age", time.Hour, "Skip devices created less than this duration ago, which may still be created")
+	profile := fs.String("profile", "", "Name of the profile in the xelon config file [$XELON_PROFILE]")
+	untagged := fs.Bool("untagged", false, "Include devices without docker-machine tags which may belong to another store")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+
+	account, err := xelon.LoadAccount(*profile)
+	if err != nil {
+		return err
+	}
+	orphans, err := account.FindOrphans(storagePath, *untagged, *minAge)
+	if err != nil {
+		return err
+	}
+
+	if *jsonOutput {
+		// keep the standard output parsable while devices are deleted
+		log.SetOutWriter(os.Stderr)
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(orphans); err != nil {
+			return err
+		}
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "LOCALVMID\tMACHINE\tHOSTNAME\tREASON\tSSH KEYS\tCREATED")
+		for _, orphan := range orphans {
+			_, _ = fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\n", orphan.LocalVMID, orphan.MachineName, orphan.Hostname,
+				orphan.Reason, strings.Join(orphan.SSHKeys, ","), orphan.CreatedAt)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if !*confirm {
+		if len(orphans) > 0 && !*jsonOutput {
+			_, _ = fmt.Fprintln(os.Stderr, "\nRun with -confirm to delete the orphaned devices.")
+		}
+		return nil
+	}
+	for _, orphan := range orphans {
+		if orphan.Protected {
+			_, _ = fmt.Fprintf(os.Stderr, "Skipping orphaned device %v (%v), it is protected against deletion\n", orphan.LocalVMID, orphan.MachineName)
+			continue
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "Deleting orphaned device %v (%v)...\n", orphan.LocalVMID, orphan.MachineName)
+		if err := account.DeleteOrphan(orphan); err != nil {
+			return fmt.Errorf("could not delete orphaned device %v: %v", orphan.LocalVMID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/docker/machine/libmachine/drivers"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon"
+)
+
+func pool(storagePath string, args []string) error {
+	fs := flag.NewFlagSet("pool", flag.ExitOnError)
+	size := fs.Int("size", 0, "Number of stopped devices which are kept in the pool")
+	createFlags := xelon.NewDriver("", "").GetCreateFlags()
+	driverOptions := registerDriverFlags(fs, createFlags)
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+
+	flagsValues, err := driverOptions()
+	if err != nil {
+		return err
+	}
+	if dryRun, _ := flagsValues["xelon-dry-run"].(bool); dryRun {
+		return fmt.Errorf("--xelon-dry-run cannot be used with pool")
+	}
+	d := xelon.NewDriver("", storagePath)
+	if err := d.SetConfigFromFlags(&drivers.CheckDriverOptions{FlagsValues: flagsValues, CreateFlags: createFlags}); err != nil {
+		return err
+	}
+	result, err := d.SyncPool(*size)
+	if result != nil {
+		for _, localVMID := range result.Created {
+			fmt.Printf("Created %v\n", localVMID)
+		}
+		for _, localVMID := range result.Deleted {
+			fmt.Printf("Deleted %v\n", localVMID)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Pool %v has %d devices\n", d.Pool, result.Size)
+	return nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon"
+)
+
+func protection(storagePath string, args []string) error {
+	fs := flag.NewFlagSet("protection", flag.ExitOnError)
+	args, err := parseArgs(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	var enabled bool
+	switch args[1] {
+	case "on":
+		enabled = true
+	case "off":
+		enabled = false
+	default:
+		return fmt.Errorf("invalid protection %q, expected on or off", args[1])
+	}
+
+	driver, err := xelon.LoadDriver(storagePath, args[0])
+	if err != nil {
+		return err
+	}
+	if err := driver.SetDeleteProtection(enabled); err != nil {
+		return err
+	}
+	return xelon.SaveDriver(driver)
+}
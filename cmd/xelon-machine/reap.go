@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/docker/machine/libmachine/log"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon"
+)
+
+func reap(storagePath string, args []string) error {
+	fs := flag.NewFlagSet("reap", flag.ExitOnError)
+	action := fs.String("action", xelon.ReapActionStop, "Action for expired devices, stop or delete")
+	dryRun := fs.Bool("dry-run", false, "Only list the expired devices")
+	gracePeriod := fs.Duration("grace-period", 0, "Skip devices whose machine in the store was active within this duration, e.g. 1h")
+	jsonOutput := fs.Bool("json", false, "Print the expired devices as JSON")
+	profile := fs.String("profile", "", "Name of the profile in the xelon config file [$XELON_PROFILE]")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	if *action != xelon.ReapActionStop && *action != xelon.ReapActionDelete {
+		return fmt.Errorf("invalid action %q, expected %v or %v", *action, xelon.ReapActionStop, xelon.ReapActionDelete)
+	}
+
+	account, err := xelon.LoadAccount(*profile)
+	if err != nil {
+		return err
+	}
+	expired, err := account.FindExpired(storagePath, *gracePeriod)
+	if err != nil {
+		return err
+	}
+
+	if *jsonOutput {
+		// keep the standard output parsable while devices are reaped
+		log.SetOutWriter(os.Stderr)
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(expired); err != nil {
+			return err
+		}
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "LOCALVMID\tMACHINE\tHOSTNAME\tEXPIRED\tLAST ACTIVITY\tSKIPPED")
+		for _, e := range expired {
+			_, _ = fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\n", e.LocalVMID, e.MachineName, e.Hostname,
+				e.ExpiresAt, e.LastActivity, e.Skipped)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if *dryRun {
+		return nil
+	}
+	for _, e := range expired {
+		if e.Skipped != "" {
+			continue
+		}
+		if e.Protected && *action == xelon.ReapActionDelete {
+			_, _ = fmt.Fprintf(os.Stderr, "Skipping expired device %v (%v), it is protected against deletion\n", e.LocalVMID, e.MachineName)
+			continue
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "Reaping expired device %v (%v) with action %v...\n", e.LocalVMID, e.MachineName, *action)
+		if err := account.ReapDevice(storagePath, e, *action); err != nil {
+			return fmt.Errorf("could not %v expired device %v: %v", *action, e.LocalVMID, err)
+		}
+	}
+	return nil
+}
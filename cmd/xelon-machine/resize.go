@@ -0,0 +1,27 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon"
+)
+
+func resize(storagePath string, args []string) error {
+	fs := flag.NewFlagSet("resize", flag.ExitOnError)
+	cpuCores := fs.Int("cpu-cores", 0, "New number of CPU cores, unchanged if not set")
+	memory := fs.Int("memory", 0, "New size of memory in GB, unchanged if not set")
+	diskSize := fs.Int("disk-size", 0, "New drive size in GB (can only grow), unchanged if not set")
+	args, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	driver, err := xelon.LoadDriver(storagePath, args[0])
+	if err != nil {
+		return err
+	}
+	if err := driver.Resize(*cpuCores, *memory, *diskSize); err != nil {
+		return err
+	}
+	return xelon.SaveDriver(driver)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon"
+)
+
+func listSnapshots(storagePath string, args []string) error {
+	args, err := parseArgs(flag.NewFlagSet("snapshots", flag.ExitOnError), args, 1)
+	if err != nil {
+		return err
+	}
+
+	driver, err := xelon.LoadDriver(storagePath, args[0])
+	if err != nil {
+		return err
+	}
+	snapshots, err := driver.ListSnapshots()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tNAME\tCREATED")
+	for _, snapshot := range snapshots {
+		_, _ = fmt.Fprintf(w, "%v\t%v\t%v\n", snapshot.ID, snapshot.Name, snapshot.CreatedAt)
+	}
+	return w.Flush()
+}
+
+func restoreSnapshot(storagePath string, args []string) error {
+	args, err := parseArgs(flag.NewFlagSet("snapshot-restore", flag.ExitOnError), args, 2)
+	if err != nil {
+		return err
+	}
+
+	driver, err := xelon.LoadDriver(storagePath, args[0])
+	if err != nil {
+		return err
+	}
+	return driver.RestoreSnapshot(args[1])
+}
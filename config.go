@@ -0,0 +1,109 @@
+package xelon
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+const defaultProfileName = "default"
+
+// config represents the configuration file of the driver with named profiles.
+type config struct {
+	Profiles map[string]profile `yaml:"profiles"`
+}
+
+// profile holds the API settings, the token source, the plan and the device defaults which are used
+// if the corresponding option is neither given as flag nor as environment variable.
+type profile struct {
+	APIBaseURL       string `yaml:"api_base_url"`
+	CPUCores         int    `yaml:"cpu_cores"`
+	CredentialHelper string `yaml:"credential_helper"`
+	DiskSize         int    `yaml:"disk_size"`
+	Memory           int    `yaml:"memory"`
+	NetworkID        int    `yaml:"network_id"`
+	Plan             string `yaml:"plan"`
+	PlanCatalog      string `yaml:"plan_catalog"`
+	SwapDiskSize     int    `yaml:"swap_disk_size"`
+	TemplateID       int    `yaml:"template_id"`
+	TenantID         string `yaml:"tenant_id"`
+	Token            string `yaml:"token"`
+}
+
+// configPath returns the path of the configuration file, which is ~/.config/xelon/config.yaml
+// unless overridden with XELON_CONFIG environment variable.
+func configPath() (string, error) {
+	if path := os.Getenv("XELON_CONFIG"); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "xelon", "config.yaml"), nil
+}
+
+// loadConfig reads the configuration file from path. A missing file results in an empty configuration.
+func loadConfig(path string) (*config, error) {
+	cfg := new(config)
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("could not read xelon config file: %v", err)
+	}
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, fmt.Errorf("could not parse xelon config file %v: %v", path, err)
+	}
+
+	return cfg, nil
+}
+
+// loadProfile returns the profile with the given name from the configuration file. If name is empty,
+// the profile named "default" is returned if it exists, otherwise an empty profile.
+func loadProfile(name string) (*profile, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		p := cfg.Profiles[defaultProfileName]
+		return &p, nil
+	}
+
+	p, ok := cfg.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("xelon profile %q not found in %v", name, path)
+	}
+	return &p, nil
+}
+
+// firstInt returns the first non-zero value.
+func firstInt(values ...int) int {
+	for _, v := range values {
+		if v != 0 {
+			return v
+		}
+	}
+	return 0
+}
+
+// firstString returns the first non-empty value.
+func firstString(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,117 @@
+package xelon
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testConfig = `
+profiles:
+  default:
+    token: default-token
+  ci:
+    api_base_url: https://vdc.example.com/api/service/
+    credential_helper: vault
+    tenant_id: tenantID
+    cpu_cores: 4
+    memory: 8
+    disk_size: 50
+    template_id: 12
+    network_id: 3
+`
+
+// TestMain points XELON_CONFIG to an empty config file, so tests which don't use useConfig never read the
+// config file of the developer.
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "xelon")
+	if err != nil {
+		panic(err)
+	}
+	path := filepath.Join(dir, "config.yaml")
+	if err := ioutil.WriteFile(path, nil, 0600); err != nil {
+		panic(err)
+	}
+	_ = os.Setenv("XELON_CONFIG", path)
+
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// useConfig writes content to a temporary config file and points XELON_CONFIG to it.
+func useConfig(t *testing.T, content string) (teardown func()) {
+	dir, err := ioutil.TempDir("", "xelon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config.yaml")
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	restoreEnv := setEnv(map[string]string{"XELON_CONFIG": path})
+
+	return func() {
+		restoreEnv()
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	teardown := useConfig(t, testConfig)
+	defer teardown()
+
+	p, err := loadProfile("ci")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &profile{
+		APIBaseURL:       "https://vdc.example.com/api/service/",
+		CPUCores:         4,
+		CredentialHelper: "vault",
+		DiskSize:         50,
+		Memory:           8,
+		NetworkID:        3,
+		TemplateID:       12,
+		TenantID:         "tenantID",
+	}, p)
+}
+
+func TestLoadProfile_default(t *testing.T) {
+	teardown := useConfig(t, testConfig)
+	defer teardown()
+
+	p, err := loadProfile("")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "default-token", p.Token)
+}
+
+func TestLoadProfile_notFound(t *testing.T) {
+	teardown := useConfig(t, testConfig)
+	defer teardown()
+
+	_, err := loadProfile("production")
+
+	assert.Error(t, err)
+}
+
+func TestLoadProfile_missingConfigFile(t *testing.T) {
+	defer setEnv(map[string]string{"XELON_CONFIG": filepath.Join(os.TempDir(), "xelon-missing-config.yaml")})()
+
+	p, err := loadProfile("")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &profile{}, p)
+}
+
+func TestLoadProfile_unknownField(t *testing.T) {
+	teardown := useConfig(t, "profiles:\n  ci:\n    cpu: 4\n")
+	defer teardown()
+
+	_, err := loadProfile("ci")
+
+	assert.Error(t, err)
+}
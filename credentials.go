@@ -0,0 +1,84 @@
+package xelon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const credentialHelperPrefix = "xelon-credential-"
+
+// credentialHelperNameRegexp restricts helper names to plain file names, so a name from a flag or a shared
+// profile cannot run a program outside the PATH.
+var credentialHelperNameRegexp = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// credentials represents the output of a credential helper in the docker-credential-helpers format.
+type credentials struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+// tokens caches the tokens provided by credential helpers for the lifetime of the process,
+// so the helper is executed only once per helper and server URL.
+var tokens = struct {
+	sync.Mutex
+	values map[string]string
+}{values: make(map[string]string)}
+
+// tokenFromCredentialHelper executes "xelon-credential-<name> get" in the style of docker-credential-helpers.
+// The server URL is written to the standard input of the helper, which must print either the credentials as
+// JSON object with the token in the Secret field or the plain token to the standard output.
+func tokenFromCredentialHelper(name, serverURL string) (string, error) {
+	if err := validateCredentialHelperName(name); err != nil {
+		return "", err
+	}
+
+	tokens.Lock()
+	defer tokens.Unlock()
+
+	key := name + "\x00" + serverURL
+	if token, ok := tokens.values[key]; ok {
+		return token, nil
+	}
+
+	program := credentialHelperPrefix + name
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(program, "get")
+	cmd.Stdin = strings.NewReader(serverURL)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = strings.TrimSpace(stdout.String())
+		}
+		return "", fmt.Errorf("credential helper %v failed: %v: %v", program, err, message)
+	}
+
+	token := strings.TrimSpace(stdout.String())
+	if strings.HasPrefix(token, "{") {
+		creds := new(credentials)
+		if err := json.Unmarshal([]byte(token), creds); err != nil {
+			return "", fmt.Errorf("credential helper %v returned invalid credentials: %v", program, err)
+		}
+		token = creds.Secret
+	}
+	if token == "" {
+		return "", fmt.Errorf("credential helper %v returned an empty token", program)
+	}
+
+	tokens.values[key] = token
+	return token, nil
+}
+
+func validateCredentialHelperName(name string) error {
+	if !credentialHelperNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid credential helper name %q, expected letters, digits, '.', '_' or '-'", name)
+	}
+	return nil
+}
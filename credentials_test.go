@@ -0,0 +1,100 @@
+package xelon
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// installCredentialHelper puts a credential helper script with the given body into a temporary
+// directory and prepends it to PATH.
+func installCredentialHelper(t *testing.T, name, body string) (teardown func()) {
+	if runtime.GOOS == "windows" {
+		t.Skip("credential helper scripts are not supported on windows")
+	}
+
+	dir, err := ioutil.TempDir("", "xelon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\n" + body + "\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, credentialHelperPrefix+name), []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+	path := os.Getenv("PATH")
+	_ = os.Setenv("PATH", dir+string(os.PathListSeparator)+path)
+
+	return func() {
+		_ = os.Setenv("PATH", path)
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func TestTokenFromCredentialHelper_json(t *testing.T) {
+	teardown := installCredentialHelper(t, "json", `read url; echo "{\"ServerURL\":\"$url\",\"Username\":\"xelon\",\"Secret\":\"secret-$1\"}"`)
+	defer teardown()
+
+	token, err := tokenFromCredentialHelper("json", "https://vdc.xelon.ch/api/service/")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "secret-get", token)
+}
+
+func TestTokenFromCredentialHelper_plain(t *testing.T) {
+	teardown := installCredentialHelper(t, "plain", `echo plain-token`)
+	defer teardown()
+
+	token, err := tokenFromCredentialHelper("plain", "https://vdc.xelon.ch/api/service/")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "plain-token", token)
+}
+
+func TestTokenFromCredentialHelper_cached(t *testing.T) {
+	teardown := installCredentialHelper(t, "cached", `echo first-token`)
+	token, err := tokenFromCredentialHelper("cached", "https://vdc.xelon.ch/api/service/")
+	teardown()
+	assert.NoError(t, err)
+
+	// the helper is not available anymore, so the token must be served from the cache
+	token, err = tokenFromCredentialHelper("cached", "https://vdc.xelon.ch/api/service/")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "first-token", token)
+}
+
+func TestTokenFromCredentialHelper_failure(t *testing.T) {
+	teardown := installCredentialHelper(t, "failure", `echo "credentials not found" >&2; exit 1`)
+	defer teardown()
+
+	_, err := tokenFromCredentialHelper("failure", "https://vdc.xelon.ch/api/service/")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "credentials not found")
+}
+
+func TestTokenFromCredentialHelper_invalidName(t *testing.T) {
+	for _, name := range []string{"", "../bin/evil", "sub/helper", "helper name"} {
+		_, err := tokenFromCredentialHelper(name, "https://vdc.xelon.ch/api/service/")
+
+		assert.Error(t, err, name)
+		assert.Contains(t, err.Error(), "invalid credential helper name", name)
+	}
+}
+
+func TestDriver_getClient_CredentialHelper(t *testing.T) {
+	teardown := installCredentialHelper(t, "driver", `echo helper-token`)
+	defer teardown()
+	driver := NewDriver("default", "path")
+	driver.CredentialHelper = "driver"
+
+	client, err := driver.getClient()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "helper-token", client.Token)
+	assert.Empty(t, driver.Token)
+}
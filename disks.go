@@ -0,0 +1,174 @@
+package xelon
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/docker/machine/libmachine/log"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon/api"
+)
+
+const (
+	defaultDataDiskFileSystem = "ext4"
+	// bytesPerGB converts the disk sizes of the Xelon API, which are given in GB of 1024^3 bytes.
+	bytesPerGB = 1 << 30
+)
+
+var supportedDataDiskFileSystems = map[string]bool{
+	"ext4": true,
+	"xfs":  true,
+}
+
+// DataDisk represents an additional disk which is attached to the device, formatted and mounted.
+type DataDisk struct {
+	FileSystem string
+	MountPoint string
+	Size       int
+}
+
+// parseDataDisk parses a data disk specification in the form "size=100,mount=/var/lib/docker,fs=ext4".
+func parseDataDisk(spec string) (DataDisk, error) {
+	disk := DataDisk{FileSystem: defaultDataDiskFileSystem}
+
+	for _, option := range strings.Split(spec, ",") {
+		kv := strings.SplitN(option, "=", 2)
+		if len(kv) != 2 {
+			return disk, fmt.Errorf("invalid data disk option %q in %q, expected key=value", option, spec)
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		switch key {
+		case "size":
+			size, err := strconv.Atoi(value)
+			if err != nil || size <= 0 {
+				return disk, fmt.Errorf("invalid data disk size %q in %q, expected size in GB", value, spec)
+			}
+			disk.Size = size
+		case "mount":
+			if !path.IsAbs(value) || path.Clean(value) == "/" || strings.ContainsAny(value, " \t\"'`$\\") {
+				return disk, fmt.Errorf("invalid data disk mount point %q in %q, expected absolute path", value, spec)
+			}
+			disk.MountPoint = path.Clean(value)
+		case "fs":
+			if !supportedDataDiskFileSystems[value] {
+				return disk, fmt.Errorf("unsupported data disk file system %q in %q, supported: ext4, xfs", value, spec)
+			}
+			disk.FileSystem = value
+		default:
+			return disk, fmt.Errorf("unknown data disk option %q in %q", key, spec)
+		}
+	}
+
+	if disk.Size == 0 {
+		return disk, fmt.Errorf("data disk %q requires the size option", spec)
+	}
+	if disk.MountPoint == "" {
+		return disk, fmt.Errorf("data disk %q requires the mount option", spec)
+	}
+
+	return disk, nil
+}
+
+// parseDataDisks parses all data disk specifications and checks that mount points are unique.
+func parseDataDisks(specs []string) ([]DataDisk, error) {
+	var disks []DataDisk
+	mountPoints := make(map[string]bool)
+	for _, spec := range specs {
+		disk, err := parseDataDisk(spec)
+		if err != nil {
+			return nil, err
+		}
+		if mountPoints[disk.MountPoint] {
+			return nil, fmt.Errorf("data disk mount point %v is used more than once", disk.MountPoint)
+		}
+		mountPoints[disk.MountPoint] = true
+		disks = append(disks, disk)
+	}
+	return disks, nil
+}
+
+// attachDataDisks attaches all configured data disks to the device.
+func (d *Driver) attachDataDisks(client *api.Client) error {
+	for i, disk := range d.DataDisks {
+		diskAddRequest := &api.DiskAddRequest{
+			Name: fmt.Sprintf("%v-data-%d", d.MachineName, i+1),
+			Size: disk.Size,
+		}
+		log.Debugf("Attaching data disk %v with %d GB...", diskAddRequest.Name, disk.Size)
+		if _, _, err := client.Disks.Add(d.LocalVMID, diskAddRequest); err != nil {
+			return fmt.Errorf("could not attach data disk for %v: %v", disk.MountPoint, err)
+		}
+	}
+	return nil
+}
+
+// prepareDataDisks partitions, formats and mounts the attached data disks over SSH. Disks are processed in the
+// order they were attached. A disk is only used if its size matches exactly and the number of unused disks of
+// that size equals the number of data disks of that size which are left, so a raw disk which already was on
+// the device, e.g. from the template, is never formatted.
+func (d *Driver) prepareDataDisks() error {
+	if _, err := runSSHCommand(d, rescanDisksCommand); err != nil {
+		return err
+	}
+
+	for i, disk := range d.DataDisks {
+		output, err := runSSHCommand(d, findUnusedDisksCommand(disk.Size))
+		if err != nil {
+			return err
+		}
+		devices := strings.Fields(output)
+		expected := 0
+		for _, other := range d.DataDisks[i:] {
+			if other.Size == disk.Size {
+				expected++
+			}
+		}
+		if len(devices) == 0 {
+			return fmt.Errorf("could not find attached data disk of %d GB for %v on the device", disk.Size, disk.MountPoint)
+		}
+		if len(devices) != expected {
+			return fmt.Errorf("found %d unused disks of %d GB on the device instead of %d, cannot tell which one was attached for %v",
+				len(devices), disk.Size, expected, disk.MountPoint)
+		}
+		device := devices[0]
+
+		log.Debugf("Formatting data disk %v with %v and mounting it to %v...", device, disk.FileSystem, disk.MountPoint)
+		if _, err := runSSHCommand(d, prepareDataDiskCommand(device, disk)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+const rescanDisksCommand = `for host in /sys/class/scsi_host/host*/scan; do echo "- - -" | sudo tee "$host" > /dev/null; done; sudo udevadm settle`
+
+// findUnusedDisksCommand returns a command which prints all disks of exactly size GB which have neither
+// partitions nor a file system.
+func findUnusedDisksCommand(size int) string {
+	return fmt.Sprintf(`for disk in $(lsblk -dbnpo NAME,TYPE,SIZE | awk '$2 == "disk" && $3 == %d { print $1 }'); do `, size*bytesPerGB) +
+		`if [ "$(lsblk -nro NAME "$disk" | wc -l)" -eq 1 ] && [ -z "$(sudo blkid -o value -s TYPE "$disk")" ]; then echo "$disk"; fi; ` +
+		`done`
+}
+
+// prepareDataDiskCommand returns a command which partitions and formats device, mounts it to the mount point
+// of the disk and adds it to /etc/fstab.
+func prepareDataDiskCommand(device string, disk DataDisk) string {
+	partition := device + "1"
+	if last := device[len(device)-1]; last >= '0' && last <= '9' {
+		// devices ending with a digit, e.g. /dev/nvme0n1, use a "p" separator for partitions
+		partition = device + "p1"
+	}
+
+	return strings.Join([]string{
+		fmt.Sprintf("sudo parted -s %v mklabel gpt mkpart primary 0%% 100%%", device),
+		"sudo udevadm settle",
+		fmt.Sprintf("sudo mkfs.%v %v", disk.FileSystem, partition),
+		fmt.Sprintf("sudo mkdir -p %v", disk.MountPoint),
+		fmt.Sprintf(`echo "UUID=$(sudo blkid -o value -s UUID %v) %v %v defaults,nofail 0 2" | sudo tee -a /etc/fstab > /dev/null`,
+			partition, disk.MountPoint, disk.FileSystem),
+		fmt.Sprintf("sudo mount %v", disk.MountPoint),
+	}, " && ")
+}
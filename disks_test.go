@@ -0,0 +1,157 @@
+package xelon
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon/api"
+)
+
+func TestParseDataDisk(t *testing.T) {
+	disk, err := parseDataDisk("size=100,mount=/var/lib/docker/,fs=xfs")
+
+	assert.NoError(t, err)
+	assert.Equal(t, DataDisk{FileSystem: "xfs", MountPoint: "/var/lib/docker", Size: 100}, disk)
+}
+
+func TestParseDataDisk_defaultFileSystem(t *testing.T) {
+	disk, err := parseDataDisk("size=50,mount=/data")
+
+	assert.NoError(t, err)
+	assert.Equal(t, DataDisk{FileSystem: "ext4", MountPoint: "/data", Size: 50}, disk)
+}
+
+func TestParseDataDisk_invalid(t *testing.T) {
+	specs := []string{
+		"",
+		"size=100",
+		"mount=/data",
+		"size=0,mount=/data",
+		"size=abc,mount=/data",
+		"size=100,mount=data",
+		"size=100,mount=/",
+		"size=100,mount=/my data",
+		"size=100,mount=/data,fs=ntfs",
+		"size=100,mount=/data,owner=root",
+		"size=100,/data",
+	}
+
+	for _, spec := range specs {
+		_, err := parseDataDisk(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestParseDataDisks_duplicateMountPoint(t *testing.T) {
+	_, err := parseDataDisks([]string{"size=100,mount=/data", "size=50,mount=/data/"})
+
+	assert.Error(t, err)
+}
+
+func TestPrepareDataDiskCommand_nvme(t *testing.T) {
+	command := prepareDataDiskCommand("/dev/nvme0n2", DataDisk{FileSystem: "ext4", MountPoint: "/data", Size: 10})
+
+	assert.Contains(t, command, "sudo mkfs.ext4 /dev/nvme0n2p1")
+}
+
+func TestDriver_Create_DataDisks(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	var diskAddRequests []api.DiskAddRequest
+	mux.HandleFunc("/vmlist/localVMID/disks/add", func(w http.ResponseWriter, r *http.Request) {
+		request := api.DiskAddRequest{}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		diskAddRequests = append(diskAddRequests, request)
+		_, _ = w.Write([]byte(`{}`))
+	})
+	standIn := useSSHStandIn(map[string][]string{
+		findUnusedDisksCommand(100): {"/dev/sdd\n"},
+		findUnusedDisksCommand(50):  {"/dev/sdc\n"},
+	})
+	driver.DataDisks = []DataDisk{
+		{FileSystem: "ext4", MountPoint: "/var/lib/docker", Size: 100},
+		{FileSystem: "xfs", MountPoint: "/data", Size: 50},
+	}
+
+	err := driver.Create()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []api.DiskAddRequest{
+		{Name: "default-data-1", Size: 100},
+		{Name: "default-data-2", Size: 50},
+	}, diskAddRequests)
+	assert.Equal(t, []string{
+		"exit 0",
+		rescanDisksCommand,
+		findUnusedDisksCommand(100),
+		"sudo parted -s /dev/sdd mklabel gpt mkpart primary 0% 100% && " +
+			"sudo udevadm settle && " +
+			"sudo mkfs.ext4 /dev/sdd1 && " +
+			"sudo mkdir -p /var/lib/docker && " +
+			`echo "UUID=$(sudo blkid -o value -s UUID /dev/sdd1) /var/lib/docker ext4 defaults,nofail 0 2" | sudo tee -a /etc/fstab > /dev/null && ` +
+			"sudo mount /var/lib/docker",
+		findUnusedDisksCommand(50),
+		"sudo parted -s /dev/sdc mklabel gpt mkpart primary 0% 100% && " +
+			"sudo udevadm settle && " +
+			"sudo mkfs.xfs /dev/sdc1 && " +
+			"sudo mkdir -p /data && " +
+			`echo "UUID=$(sudo blkid -o value -s UUID /dev/sdc1) /data xfs defaults,nofail 0 2" | sudo tee -a /etc/fstab > /dev/null && ` +
+			"sudo mount /data",
+	}, standIn.commands)
+}
+
+func TestDriver_Create_DataDiskNotFound(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	mux.HandleFunc("/vmlist/localVMID/disks/add", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	})
+	useSSHStandIn(map[string][]string{})
+	driver.DataDisks = []DataDisk{{FileSystem: "ext4", MountPoint: "/var/lib/docker", Size: 100}}
+
+	err := driver.Create()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "/var/lib/docker")
+}
+
+func TestDriver_Create_DataDiskAmbiguous(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	mux.HandleFunc("/vmlist/localVMID/disks/add", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	})
+	// a raw disk of the same size which was already on the device
+	standIn := useSSHStandIn(map[string][]string{
+		findUnusedDisksCommand(100): {"/dev/sdb\n/dev/sdc\n"},
+	})
+	driver.DataDisks = []DataDisk{{FileSystem: "ext4", MountPoint: "/var/lib/docker", Size: 100}}
+
+	err := driver.Create()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "found 2 unused disks of 100 GB")
+	for _, command := range standIn.commands {
+		assert.NotContains(t, command, "mkfs")
+	}
+}
+
+func TestFindUnusedDisksCommand(t *testing.T) {
+	assert.Contains(t, findUnusedDisksCommand(100), `$3 == 107374182400`)
+}
+
+func TestDriver_Create_WithoutDataDisks(t *testing.T) {
+	driver, _, teardown := setup("default")
+	defer teardown()
+	standIn := useSSHStandIn(map[string][]string{})
+
+	err := driver.Create()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "localVMID", driver.LocalVMID)
+	assert.Equal(t, "10.0.0.10", driver.IPAddress)
+	assert.Empty(t, standIn.commands)
+}
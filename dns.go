@@ -0,0 +1,150 @@
+package xelon
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/docker/machine/libmachine/log"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon/api"
+)
+
+const defaultDNSTTL = 300
+
+// DNSRecord represents a DNS record which was created for the machine and is deleted when it is removed.
+type DNSRecord struct {
+	ID     int
+	Name   string
+	Type   string
+	ZoneID int
+}
+
+// normalizeZoneName returns name in lower case without trailing dot.
+func normalizeZoneName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+// findDNSZone returns the zone with the given name.
+func findDNSZone(zones []api.DNSZone, name string) (api.DNSZone, bool) {
+	for _, zone := range zones {
+		if normalizeZoneName(zone.Name) == normalizeZoneName(name) {
+			return zone, true
+		}
+	}
+	return api.DNSZone{}, false
+}
+
+// findReverseZone returns the most specific zone which contains the reverse name, together with the
+// name relative to that zone.
+func findReverseZone(zones []api.DNSZone, reverseName string) (api.DNSZone, string, bool) {
+	var found api.DNSZone
+	var foundName string
+	for _, zone := range zones {
+		name := normalizeZoneName(zone.Name)
+		if strings.HasSuffix(reverseName, "."+name) && len(name) > len(foundName) {
+			found = zone
+			foundName = name
+		}
+	}
+	if foundName == "" {
+		return found, "", false
+	}
+	return found, strings.TrimSuffix(reverseName, "."+foundName), true
+}
+
+// reverseName returns the name of the PTR record of ip, e.g. "10.0.0.10.in-addr.arpa" for 10.0.0.10.
+func reverseName(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return fmt.Sprintf("%d.%d.%d.%d.in-addr.arpa", ip4[3], ip4[2], ip4[1], ip4[0])
+	}
+
+	const hexDigits = "0123456789abcdef"
+	ip16 := ip.To16()
+	labels := make([]string, 0, 2*len(ip16)+1)
+	for i := len(ip16) - 1; i >= 0; i-- {
+		labels = append(labels, string(hexDigits[ip16[i]&0x0f]), string(hexDigits[ip16[i]>>4]))
+	}
+	return strings.Join(append(labels, "ip6.arpa"), ".")
+}
+
+// checkDNSZone checks that the DNS zone exists in the tenant.
+func (d *Driver) checkDNSZone(client *api.Client) error {
+	zones, _, err := client.DNS.ListZones(d.TenantID)
+	if err != nil {
+		return fmt.Errorf("could not list DNS zones: %v", err)
+	}
+	if _, ok := findDNSZone(zones, d.DNSZone); !ok {
+		return fmt.Errorf("xelon-dns-zone %q not found", d.DNSZone)
+	}
+	return nil
+}
+
+// registerDNSRecords creates an A or AAAA record for the machine in the DNS zone and a PTR record in the
+// reverse zone of its address, if such a zone exists. If a record cannot be created, the records created
+// so far are deleted again.
+func (d *Driver) registerDNSRecords(client *api.Client) error {
+	ip := net.ParseIP(d.IPAddress)
+	if ip == nil {
+		return fmt.Errorf("could not register DNS records: invalid IP address %q", d.IPAddress)
+	}
+	zones, _, err := client.DNS.ListZones(d.TenantID)
+	if err != nil {
+		return fmt.Errorf("could not list DNS zones: %v", err)
+	}
+	zone, ok := findDNSZone(zones, d.DNSZone)
+	if !ok {
+		return fmt.Errorf("xelon-dns-zone %q not found", d.DNSZone)
+	}
+
+	recordType := api.DNSRecordTypeA
+	if ip.To4() == nil {
+		recordType = api.DNSRecordTypeAAAA
+	}
+	if err := d.createDNSRecord(client, zone, &api.DNSRecord{Content: ip.String(), Name: d.MachineName, Type: recordType}); err != nil {
+		return err
+	}
+
+	reverseZone, name, ok := findReverseZone(zones, reverseName(ip))
+	if !ok {
+		log.Debugf("No reverse DNS zone for %v found, skipping PTR record", ip)
+		return nil
+	}
+	fqdn := fmt.Sprintf("%v.%v.", d.MachineName, normalizeZoneName(zone.Name))
+	if err := d.createDNSRecord(client, reverseZone, &api.DNSRecord{Content: fqdn, Name: name, Type: api.DNSRecordTypePTR}); err != nil {
+		if removeErr := d.removeDNSRecords(client); removeErr != nil {
+			log.Warn(removeErr)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (d *Driver) createDNSRecord(client *api.Client, zone api.DNSZone, record *api.DNSRecord) error {
+	record.TTL = d.DNSTTL
+	log.Debugf("Creating DNS %v record %v in zone %v...", record.Type, record.Name, zone.Name)
+	created, _, err := client.DNS.CreateRecord(zone.ID, record)
+	if err != nil {
+		return fmt.Errorf("could not create DNS %v record %v in zone %v: %v", record.Type, record.Name, zone.Name, err)
+	}
+	d.DNSRecords = append(d.DNSRecords, DNSRecord{ID: created.ID, Name: record.Name, Type: record.Type, ZoneID: zone.ID})
+	return nil
+}
+
+// removeDNSRecords deletes the DNS records created by the driver. Records which don't exist anymore
+// are ignored.
+func (d *Driver) removeDNSRecords(client *api.Client) error {
+	for len(d.DNSRecords) > 0 {
+		record := d.DNSRecords[0]
+		log.Debugf("Deleting DNS %v record %v...", record.Type, record.Name)
+		if resp, err := client.DNS.DeleteRecord(record.ZoneID, record.ID); err != nil {
+			if resp == nil || resp.StatusCode != http.StatusNotFound {
+				return fmt.Errorf("could not delete DNS %v record %v: %v", record.Type, record.Name, err)
+			}
+		}
+		d.DNSRecords = d.DNSRecords[1:]
+	}
+	return nil
+}
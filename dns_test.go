@@ -0,0 +1,134 @@
+package xelon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon/api"
+)
+
+const testDNSZones = `[
+	{"id":1,"name":"example.com"},
+	{"id":2,"name":"10.in-addr.arpa"},
+	{"id":3,"name":"0.0.10.in-addr.arpa."}
+]`
+
+func TestReverseName(t *testing.T) {
+	assert.Equal(t, "10.0.0.10.in-addr.arpa", reverseName(net.ParseIP("10.0.0.10")))
+	assert.Equal(t, "0.1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa",
+		reverseName(net.ParseIP("2001:db8::10")))
+}
+
+func TestFindReverseZone(t *testing.T) {
+	var zones []api.DNSZone
+	_ = json.Unmarshal([]byte(testDNSZones), &zones)
+
+	zone, name, ok := findReverseZone(zones, "10.0.0.10.in-addr.arpa")
+	assert.True(t, ok)
+	assert.Equal(t, 3, zone.ID)
+	assert.Equal(t, "10", name)
+
+	_, _, ok = findReverseZone(zones, "10.0.168.192.in-addr.arpa")
+	assert.False(t, ok)
+}
+
+func TestDriver_PreCreateCheck_DNSZoneNotFound(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.DNSZone = "example.org"
+	mux.HandleFunc("/dns/zones", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, testDNSZones)
+	})
+
+	err := driver.PreCreateCheck()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "example.org")
+}
+
+func TestDriver_Create_DNSRecords(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.DNSTTL = 60
+	driver.DNSZone = "example.com"
+	mux.HandleFunc("/dns/zones", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, testDNSZones)
+	})
+	var created []string
+	mux.HandleFunc("/dns/zones/", func(w http.ResponseWriter, r *http.Request) {
+		record := api.DNSRecord{}
+		_ = json.NewDecoder(r.Body).Decode(&record)
+		created = append(created, fmt.Sprintf("%v %v %v %v %d", r.URL.Path, record.Type, record.Name, record.Content, record.TTL))
+		_, _ = fmt.Fprintf(w, `{"id":%d}`, len(created))
+	})
+
+	err := driver.Create()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"/dns/zones/1/records A default 10.0.0.10 60",
+		"/dns/zones/3/records PTR 10 default.example.com. 60",
+	}, created)
+	assert.Equal(t, []DNSRecord{
+		{ID: 1, Name: "default", Type: api.DNSRecordTypeA, ZoneID: 1},
+		{ID: 2, Name: "10", Type: api.DNSRecordTypePTR, ZoneID: 3},
+	}, driver.DNSRecords)
+}
+
+func TestDriver_Create_DNSRecordsRollback(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.DNSTTL = 60
+	driver.DNSZone = "example.com"
+	mux.HandleFunc("/dns/zones", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, testDNSZones)
+	})
+	mux.HandleFunc("/dns/zones/1/records", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"id":7}`)
+	})
+	mux.HandleFunc("/dns/zones/3/records", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	})
+	deleted := false
+	mux.HandleFunc("/dns/zones/1/records/7", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		deleted = true
+	})
+
+	err := driver.Create()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "PTR")
+	assert.True(t, deleted)
+	assert.Empty(t, driver.DNSRecords)
+}
+
+func TestDriver_Remove_DNSRecords(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.DNSRecords = []DNSRecord{
+		{ID: 1, Name: "default", Type: api.DNSRecordTypeA, ZoneID: 1},
+		{ID: 2, Name: "10", Type: api.DNSRecordTypePTR, ZoneID: 3},
+	}
+	driver.LocalVMID = "localVMID"
+	driver.TenantID = "tenantID"
+	var deleted []string
+	mux.HandleFunc("/dns/zones/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		deleted = append(deleted, r.URL.Path)
+		if r.URL.Path == "/dns/zones/3/records/2" {
+			http.NotFound(w, r)
+		}
+	})
+
+	err := driver.Remove()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/dns/zones/1/records/1", "/dns/zones/3/records/2"}, deleted)
+	assert.Empty(t, driver.DNSRecords)
+}
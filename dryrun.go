@@ -0,0 +1,85 @@
+package xelon
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/docker/machine/libmachine/log"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon/api"
+)
+
+// dryRunLocalVMID replaces the localvmid of the device in a dry run, since it is only known once the device
+// exists.
+const dryRunLocalVMID = "LOCALVMID"
+
+// dryRunSSHPublicKey is shown instead of the public key of the machine, which is only generated when the
+// device is created.
+const dryRunSSHPublicKey = "SSHPUBLICKEY"
+
+// errDryRun is returned by Create in a dry run, so docker-machine exits with an error and doesn't treat the
+// machine as created.
+var errDryRun = errors.New("dry run, no device was created")
+
+// dryRunCreate logs the requests which Create sends to create the device instead of sending them. Lookups are
+// done as usual, except for the device which doesn't exist, waiting for the device to be provisioned and to
+// get an address is skipped. DNS records are only shown for a static IP address, since the address is not
+// known otherwise. Nothing is written to the store and the driver doesn't refer to any resource afterwards.
+func (d *Driver) dryRunCreate(client *api.Client) error {
+	defer func() {
+		d.DNSRecords = nil
+		d.FirewallRuleIDs = nil
+		d.IPAddress = ""
+		d.LocalVMID = ""
+		d.ReservedIPAddress = ""
+	}()
+
+	if d.StaticIPAddress != "" {
+		if err := d.reserveIPAddress(client); err != nil {
+			return err
+		}
+	}
+	if _, err := d.createDevice(); err != nil {
+		return err
+	}
+	d.LocalVMID = dryRunLocalVMID
+	d.IPAddress = d.StaticIPAddress
+
+	if err := d.ensureFirewallRules(client); err != nil {
+		return err
+	}
+	if _, err := client.SSHs.Add(d.LocalVMID, &api.SSHAddRequest{Name: d.MachineName, SSHKey: dryRunSSHPublicKey}); err != nil {
+		return err
+	}
+	if err := d.attachDataDisks(client); err != nil {
+		return err
+	}
+	if _, err := client.Devices.Start(d.LocalVMID); err != nil {
+		return err
+	}
+	if d.DNSZone != "" {
+		if d.IPAddress != "" {
+			if err := d.registerDNSRecords(client); err != nil {
+				return err
+			}
+		} else {
+			log.Infof("DNS records of %v.%v depend on the IP address of the device and are not shown", d.MachineName, d.DNSZone)
+		}
+	}
+
+	log.Infof("Dry run, the following requests would be sent to %v:\n%v", client.BaseURL, formatRecordedRequests(d.recorder.Requests))
+	return errDryRun
+}
+
+// formatRecordedRequests returns the requests one per line, followed by their body if they have one.
+func formatRecordedRequests(requests []api.RecordedRequest) string {
+	var b strings.Builder
+	for _, request := range requests {
+		_, _ = fmt.Fprintf(&b, "%v %v\n", request.Method, request.URL)
+		if request.Body != "" {
+			_, _ = fmt.Fprintf(&b, "    %v\n", request.Body)
+		}
+	}
+	return b.String()
+}
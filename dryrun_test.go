@@ -0,0 +1,107 @@
+package xelon
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// assertGolden compares actual with the golden file testdata/<name>.golden, which is written instead with -update.
+func assertGolden(t *testing.T, name, actual string) {
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(actual), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(expected), actual)
+}
+
+func TestDriver_Create_DryRun(t *testing.T) {
+	tests := map[string]func(driver *Driver, mux *http.ServeMux){
+		"clone": func(driver *Driver, mux *http.ServeMux) {
+			driver.CloneFrom = "golden"
+			mux.HandleFunc("/templates", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = fmt.Fprint(w, `[]`)
+			})
+		},
+		"default": func(driver *Driver, mux *http.ServeMux) {},
+		"full": func(driver *Driver, mux *http.ServeMux) {
+			driver.DataDisks = []DataDisk{{FileSystem: "ext4", MountPoint: "/var/lib/docker", Size: 100}}
+			driver.DNSTTL = 60
+			driver.DNSZone = "example.com"
+			driver.HVSystemID = 3
+			driver.NetworkID = 5
+			driver.StaticIPAddress = "10.0.0.20"
+			driver.Tags = map[string]string{"team": "platform"}
+			driver.TemplateID = 7
+			driver.TTL = 6 * time.Hour
+			mux.HandleFunc("/dns/zones", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = fmt.Fprint(w, testDNSZones)
+			})
+		},
+	}
+
+	now = func() time.Time { return time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+	for name, configure := range tests {
+		t.Run(name, func(t *testing.T) {
+			driver, mux, teardown := setup("ci-runner-1")
+			defer teardown()
+			driver.DryRun = true
+			configure(driver, mux)
+			for _, pattern := range []string{"/vmlist/create", "/vmlist/LOCALVMID/", "/firewalls/create", "/networks/", "/dns/zones/"} {
+				mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+					t.Errorf("unexpected request %v %v in dry run", r.Method, r.URL)
+				})
+			}
+			mux.HandleFunc("/firewalls", func(w http.ResponseWriter, r *http.Request) {
+				t.Errorf("unexpected request %v %v in dry run", r.Method, r.URL)
+			})
+
+			err := driver.Create()
+
+			assert.Equal(t, errDryRun, err)
+			_, err = os.Stat(driver.GetSSHKeyPath())
+			assert.True(t, os.IsNotExist(err), "SSH key must not be generated in a dry run")
+			assert.Empty(t, driver.LocalVMID)
+			assert.Empty(t, driver.IPAddress)
+			assert.Empty(t, driver.ReservedIPAddress)
+			assert.Empty(t, driver.FirewallRuleIDs)
+			assert.Empty(t, driver.DNSRecords)
+			requests := strings.NewReplacer(
+				storePathHash(driver.StorePath), "STOREHASH",
+			).Replace(formatRecordedRequests(driver.recorder.Requests))
+			assertGolden(t, filepath.Join("dry-run", name), requests)
+		})
+	}
+}
+
+func TestDriver_Remove_neverCreated(t *testing.T) {
+	driver, mux, teardown := setup("ci-runner-1")
+	defer teardown()
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %v %v", r.Method, r.URL)
+	})
+
+	err := driver.Remove()
+
+	assert.NoError(t, err)
+}
@@ -0,0 +1,134 @@
+package xelon
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/docker/machine/libmachine/log"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon/api"
+)
+
+const (
+	defaultAllowedSourceCIDR     = "0.0.0.0/0"
+	defaultAllowedSourceCIDRIPv6 = "::/0"
+)
+
+// validateCIDR checks that cidr is a valid network in CIDR notation.
+func validateCIDR(cidr string) error {
+	if _, _, err := net.ParseCIDR(cidr); err != nil {
+		return fmt.Errorf("xelon-allowed-source-cidr %q is not a valid CIDR: %v", cidr, err)
+	}
+	return nil
+}
+
+// firewallPorts returns the ports which must be reachable from the allowed source CIDR.
+func (d *Driver) firewallPorts() []int {
+	sshPort, _ := d.GetSSHPort()
+	return []int{sshPort, d.getEnginePort()}
+}
+
+// firewallSourceCIDR returns the source network of the firewall rules. The default source network is
+// replaced with its IPv6 counterpart if the device is connected with an IPv6 address. A source network
+// of another family than the device address is an error, since the device would not be reachable.
+func (d *Driver) firewallSourceCIDR() (string, error) {
+	deviceIP := net.ParseIP(d.IPAddress)
+	if deviceIP == nil {
+		return d.AllowedSourceCIDR, nil
+	}
+	deviceIPv4 := deviceIP.To4() != nil
+	if !deviceIPv4 && d.AllowedSourceCIDR == defaultAllowedSourceCIDR {
+		return defaultAllowedSourceCIDRIPv6, nil
+	}
+
+	if err := validateCIDR(d.AllowedSourceCIDR); err != nil {
+		return "", err
+	}
+	_, network, _ := net.ParseCIDR(d.AllowedSourceCIDR)
+	if (network.IP.To4() != nil) != deviceIPv4 {
+		return "", fmt.Errorf("xelon-allowed-source-cidr %v and the device address %v are of different IP families", d.AllowedSourceCIDR, d.IPAddress)
+	}
+	return d.AllowedSourceCIDR, nil
+}
+
+// ensureFirewallRules creates inbound rules for the SSH and Docker engine ports of the device unless matching
+// rules already exist. The IDs of created rules are stored, so Remove can delete them.
+func (d *Driver) ensureFirewallRules(client *api.Client) error {
+	sourceCIDR, err := d.firewallSourceCIDR()
+	if err != nil {
+		return err
+	}
+	var rules []api.FirewallRule
+	// the device of a dry run doesn't exist, so it has no rules
+	if !d.DryRun {
+		rules, _, err = client.Firewalls.List(d.TenantID, &api.FirewallRuleListOptions{LocalVMID: d.LocalVMID})
+		if err != nil {
+			return fmt.Errorf("could not list firewall rules: %v", err)
+		}
+	}
+
+	for _, port := range d.firewallPorts() {
+		if hasFirewallRule(rules, port, sourceCIDR) {
+			log.Debugf("Firewall rule for port %d from %v already exists", port, sourceCIDR)
+			continue
+		}
+
+		log.Debugf("Creating firewall rule for port %d from %v...", port, sourceCIDR)
+		rule, _, err := client.Firewalls.Create(&api.FirewallRule{
+			Direction:  api.FirewallDirectionInbound,
+			LocalVMID:  d.LocalVMID,
+			Name:       fmt.Sprintf("%v%d", firewallRuleNamePrefix(d.MachineName), port),
+			Port:       port,
+			Protocol:   api.FirewallProtocolTCP,
+			SourceCIDR: sourceCIDR,
+		})
+		if err != nil {
+			return fmt.Errorf("could not create firewall rule for port %d: %v", port, err)
+		}
+		d.FirewallRuleIDs = append(d.FirewallRuleIDs, rule.ID)
+	}
+
+	return nil
+}
+
+// firewallRuleNamePrefix returns the prefix of the names of the firewall rules created for a machine.
+func firewallRuleNamePrefix(machineName string) string {
+	return fmt.Sprintf("docker-machine-%v-", machineName)
+}
+
+func hasFirewallRule(rules []api.FirewallRule, port int, sourceCIDR string) bool {
+	for _, rule := range rules {
+		if rule.Direction == api.FirewallDirectionInbound && rule.Protocol == api.FirewallProtocolTCP &&
+			rule.Port == port && sameCIDR(rule.SourceCIDR, sourceCIDR) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameCIDR reports whether a and b denote the same network, e.g. "2001:DB8::/32" and "2001:db8::/32".
+func sameCIDR(a, b string) bool {
+	_, networkA, errA := net.ParseCIDR(a)
+	_, networkB, errB := net.ParseCIDR(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return networkA.String() == networkB.String()
+}
+
+// removeFirewallRules deletes the firewall rules created by the driver. Rules which don't exist anymore
+// are ignored.
+func (d *Driver) removeFirewallRules(client *api.Client) error {
+	for len(d.FirewallRuleIDs) > 0 {
+		ruleID := d.FirewallRuleIDs[0]
+		log.Debugf("Deleting firewall rule %d...", ruleID)
+		if resp, err := client.Firewalls.Delete(ruleID); err != nil {
+			if resp == nil || resp.StatusCode != http.StatusNotFound {
+				return fmt.Errorf("could not delete firewall rule %d: %v", ruleID, err)
+			}
+		}
+		d.FirewallRuleIDs = d.FirewallRuleIDs[1:]
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package xelon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon/api"
+)
+
+func TestValidateCIDR(t *testing.T) {
+	assert.NoError(t, validateCIDR("0.0.0.0/0"))
+	assert.NoError(t, validateCIDR("203.0.113.0/24"))
+	assert.NoError(t, validateCIDR("2001:db8::/32"))
+	assert.Error(t, validateCIDR("203.0.113.1"))
+	assert.Error(t, validateCIDR("office"))
+}
+
+func TestSameCIDR(t *testing.T) {
+	assert.True(t, sameCIDR("2001:DB8::/32", "2001:db8::/32"))
+	assert.True(t, sameCIDR("203.0.113.0/24", "203.0.113.0/24"))
+	assert.False(t, sameCIDR("203.0.113.0/24", "203.0.113.0/25"))
+}
+
+func TestDriver_firewallSourceCIDR(t *testing.T) {
+	tests := map[string]struct {
+		allowedSourceCIDR string
+		ipAddress         string
+		want              string
+		wantErr           bool
+	}{
+		"ipv4 default":    {defaultAllowedSourceCIDR, "10.0.0.10", "0.0.0.0/0", false},
+		"ipv6 default":    {defaultAllowedSourceCIDR, "2001:db8::10", "::/0", false},
+		"ipv4 network":    {"203.0.113.0/24", "10.0.0.10", "203.0.113.0/24", false},
+		"ipv6 network":    {"2001:db8:1::/48", "2001:db8::10", "2001:db8:1::/48", false},
+		"ipv4 for ipv6":   {"203.0.113.0/24", "2001:db8::10", "", true},
+		"ipv6 for ipv4":   {"2001:db8:1::/48", "10.0.0.10", "", true},
+		"unknown address": {"203.0.113.0/24", "", "203.0.113.0/24", false},
+		"invalid network": {"office", "10.0.0.10", "", true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			driver := NewDriver("default", "path")
+			driver.AllowedSourceCIDR = test.allowedSourceCIDR
+			driver.IPAddress = test.ipAddress
+
+			sourceCIDR, err := driver.firewallSourceCIDR()
+
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, sourceCIDR)
+			}
+		})
+	}
+}
+
+func TestDriver_Create_FirewallRules(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.AllowedSourceCIDR = "203.0.113.0/24"
+	mux.HandleFunc("/firewalls", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "localVMID", r.URL.Query().Get("localvmid"))
+		_, _ = fmt.Fprint(w, `[
+			{"id":7,"direction":"inbound","port":22,"protocol":"tcp","source":"203.0.113.0/24"},
+			{"id":8,"direction":"inbound","port":2376,"protocol":"tcp","source":"0.0.0.0/0"}
+		]`)
+	})
+	var createdRules []api.FirewallRule
+	mux.HandleFunc("/firewalls/create", func(w http.ResponseWriter, r *http.Request) {
+		rule := api.FirewallRule{}
+		_ = json.NewDecoder(r.Body).Decode(&rule)
+		createdRules = append(createdRules, rule)
+		_, _ = fmt.Fprint(w, `{"id":9}`)
+	})
+
+	err := driver.Create()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []api.FirewallRule{{
+		Direction:  api.FirewallDirectionInbound,
+		LocalVMID:  "localVMID",
+		Name:       "docker-machine-default-2376",
+		Port:       2376,
+		Protocol:   api.FirewallProtocolTCP,
+		SourceCIDR: "203.0.113.0/24",
+	}}, createdRules)
+	assert.Equal(t, []int{9}, driver.FirewallRuleIDs)
+}
+
+func TestDriver_Create_FirewallRulesEnginePort(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.EnginePort = 12376
+	var ports []int
+	mux.HandleFunc("/firewalls/create", func(w http.ResponseWriter, r *http.Request) {
+		rule := api.FirewallRule{}
+		_ = json.NewDecoder(r.Body).Decode(&rule)
+		ports = append(ports, rule.Port)
+		_, _ = fmt.Fprintf(w, `{"id":%d}`, rule.Port)
+	})
+
+	err := driver.Create()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{22, 12376}, ports)
+}
+
+func TestDriver_Create_FirewallRulesIPv6(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.IPFamily = ipFamilyIPv6
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{
+			"device":{"localvmdetails":{"state":1},"networks":[{"ip":"10.0.0.10","ipv6":"2001:db8::10"}],"powerstate":true},
+			"toolsStatus":{"runningStatus":"guestToolsRunning"}
+		}`)
+	})
+	var sourceCIDRs []string
+	mux.HandleFunc("/firewalls/create", func(w http.ResponseWriter, r *http.Request) {
+		rule := api.FirewallRule{}
+		_ = json.NewDecoder(r.Body).Decode(&rule)
+		sourceCIDRs = append(sourceCIDRs, rule.SourceCIDR)
+		_, _ = fmt.Fprint(w, `{"id":1}`)
+	})
+
+	err := driver.Create()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2001:db8::10", driver.IPAddress)
+	assert.Equal(t, []string{"::/0", "::/0"}, sourceCIDRs)
+}
+
+func TestDriver_Remove_FirewallRules(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.LocalVMID = "localVMID"
+	driver.TenantID = "tenantID"
+	driver.FirewallRuleIDs = []int{9, 10}
+	var deletedRules []string
+	mux.HandleFunc("/firewalls/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		deletedRules = append(deletedRules, r.URL.Path)
+		if r.URL.Path == "/firewalls/10" {
+			http.NotFound(w, r)
+		}
+	})
+
+	err := driver.Remove()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/firewalls/9", "/firewalls/10"}, deletedRules)
+	assert.Empty(t, driver.FirewallRuleIDs)
+}
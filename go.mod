@@ -9,6 +9,7 @@ require (
 	github.com/docker/machine v0.16.2
 	github.com/stretchr/testify v1.4.0
 	golang.org/x/crypto v0.0.0-20191107222254-f4817d981bb6 // indirect
+	gopkg.in/yaml.v2 v2.2.2
 )
 
 replace github.com/Sirupsen/logrus => github.com/sirupsen/logrus v1.0.5
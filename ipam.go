@@ -0,0 +1,71 @@
+package xelon
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/docker/machine/libmachine/log"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon/api"
+)
+
+// validateStaticIPAddress checks that address is a valid IP address and that the network is known.
+func validateStaticIPAddress(address string, networkID int) error {
+	if net.ParseIP(address) == nil {
+		return fmt.Errorf("xelon-ip-address %q is not a valid IP address", address)
+	}
+	if networkID == 0 {
+		return fmt.Errorf("xelon-ip-address requires xelon-network-id")
+	}
+	return nil
+}
+
+// reserveIPAddress reserves the static IP address of the device. An address which is already reserved,
+// e.g. kept from a removed machine with xelon-keep-ip, is only taken over if it isn't assigned to a device.
+// The machine doesn't own a reservation which it took over, so it is never released by the machine.
+func (d *Driver) reserveIPAddress(client *api.Client) error {
+	_, resp, err := client.IPAM.Reserve(d.NetworkID, d.StaticIPAddress)
+	if err == nil {
+		d.ReservedIPAddress = d.StaticIPAddress
+		return nil
+	}
+	if resp == nil || resp.StatusCode != http.StatusConflict {
+		return fmt.Errorf("could not reserve IP address %v: %v", d.StaticIPAddress, err)
+	}
+
+	address, _, err := client.IPAM.Get(d.NetworkID, d.StaticIPAddress)
+	if err != nil {
+		return fmt.Errorf("could not check existing reservation of IP address %v: %v", d.StaticIPAddress, err)
+	}
+	if address.LocalVMID != "" {
+		return fmt.Errorf("IP address %v is already assigned to device %v", d.StaticIPAddress, address.LocalVMID)
+	}
+	if !address.Reserved {
+		return fmt.Errorf("IP address %v could not be reserved and is not reserved either", d.StaticIPAddress)
+	}
+	log.Infof("IP address %v is already reserved and not assigned to a device, using the existing reservation", d.StaticIPAddress)
+	return nil
+}
+
+// releaseIPAddress releases the reserved IP address of the device unless it should be kept. Reservations
+// which don't exist anymore are ignored.
+func (d *Driver) releaseIPAddress(client *api.Client) error {
+	if d.ReservedIPAddress == "" {
+		return nil
+	}
+	if d.KeepIP {
+		log.Infof("Keeping reservation of IP address %v", d.ReservedIPAddress)
+		return nil
+	}
+
+	resp, err := client.IPAM.Release(d.NetworkID, d.ReservedIPAddress)
+	if err != nil {
+		if resp == nil || resp.StatusCode != http.StatusNotFound {
+			return fmt.Errorf("could not release IP address %v: %v", d.ReservedIPAddress, err)
+		}
+		log.Debugf("Reservation of IP address %v doesn't exist anymore", d.ReservedIPAddress)
+	}
+	d.ReservedIPAddress = ""
+	return nil
+}
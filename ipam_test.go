@@ -0,0 +1,189 @@
+package xelon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateStaticIPAddress(t *testing.T) {
+	assert.NoError(t, validateStaticIPAddress("10.0.0.20", 7))
+	assert.NoError(t, validateStaticIPAddress("2001:db8::20", 7))
+	assert.Error(t, validateStaticIPAddress("10.0.0.256", 7))
+	assert.Error(t, validateStaticIPAddress("10.0.0.20", 0))
+}
+
+func TestDriver_SetConfigFromFlags_IPAddressWithoutNetwork(t *testing.T) {
+	teardown := useConfig(t, "")
+	defer teardown()
+	driver := NewDriver("default", "path")
+	flags := &drivers.CheckDriverOptions{
+		FlagsValues: map[string]interface{}{
+			"xelon-ip-address": "10.0.0.20",
+			"xelon-token":      "token",
+		},
+		CreateFlags: driver.GetCreateFlags(),
+	}
+
+	err := driver.SetConfigFromFlags(flags)
+
+	assert.Error(t, err)
+}
+
+func TestDriver_Create_StaticIPAddress(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.NetworkID = 7
+	driver.StaticIPAddress = "10.0.0.20"
+	var reserved string
+	mux.HandleFunc("/networks/7/ips/reserve", func(w http.ResponseWriter, r *http.Request) {
+		request := new(struct {
+			Address string `json:"ip"`
+		})
+		_ = json.NewDecoder(r.Body).Decode(request)
+		reserved = request.Address
+		_, _ = fmt.Fprint(w, `{"ip":"10.0.0.20","reserved":true}`)
+	})
+	mux.HandleFunc("/vmlist/create", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "10.0.0.20", reserved, "address must be reserved before the device is created")
+		assert.Equal(t, "10.0.0.20", r.URL.Query().Get("ip_address"))
+		_, _ = fmt.Fprint(w, `{"device":{"localvmid":"localVMID"},"ips":["10.0.0.20"]}`)
+	})
+
+	err := driver.Create()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.20", driver.ReservedIPAddress)
+}
+
+func TestDriver_Create_StaticIPAddressAlreadyReserved(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.NetworkID = 7
+	driver.StaticIPAddress = "10.0.0.20"
+	mux.HandleFunc("/networks/7/ips/reserve", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	})
+	mux.HandleFunc("/networks/7/ips/10.0.0.20", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"ip":"10.0.0.20","network_id":7,"reserved":true}`)
+	})
+	mux.HandleFunc("/vmlist/create", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "10.0.0.20", r.URL.Query().Get("ip_address"))
+		_, _ = fmt.Fprint(w, `{"device":{"localvmid":"localVMID"},"ips":["10.0.0.20"]}`)
+	})
+
+	err := driver.Create()
+
+	assert.NoError(t, err)
+	assert.Empty(t, driver.ReservedIPAddress, "a reservation which was taken over must not be released by the machine")
+}
+
+func TestDriver_Create_StaticIPAddressAssigned(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.NetworkID = 7
+	driver.StaticIPAddress = "10.0.0.20"
+	mux.HandleFunc("/networks/7/ips/reserve", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	})
+	mux.HandleFunc("/networks/7/ips/10.0.0.20", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"ip":"10.0.0.20","localvmid":"other","network_id":7,"reserved":true}`)
+	})
+	mux.HandleFunc("/networks/7/ips/release", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("reservation of another device must not be released")
+	})
+	mux.HandleFunc("/vmlist/create", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected create of a device")
+	})
+
+	err := driver.Create()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "other")
+	assert.Empty(t, driver.ReservedIPAddress)
+}
+
+func TestDriver_Create_StaticIPAddressConflictUnknown(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.NetworkID = 7
+	driver.StaticIPAddress = "10.0.0.20"
+	mux.HandleFunc("/networks/7/ips/reserve", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	})
+	mux.HandleFunc("/networks/7/ips/10.0.0.20", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	mux.HandleFunc("/vmlist/create", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected create of a device")
+	})
+
+	err := driver.Create()
+
+	assert.Error(t, err)
+}
+
+func TestDriver_Create_KeepsTakenOverIPAddressOnFailure(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.NetworkID = 7
+	driver.StaticIPAddress = "10.0.0.20"
+	mux.HandleFunc("/networks/7/ips/reserve", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	})
+	mux.HandleFunc("/networks/7/ips/10.0.0.20", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"ip":"10.0.0.20","network_id":7,"reserved":true}`)
+	})
+	mux.HandleFunc("/networks/7/ips/release", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("reservation which was taken over must not be released")
+	})
+	mux.HandleFunc("/vmlist/create", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	})
+
+	err := driver.Create()
+
+	assert.Error(t, err)
+}
+
+func TestDriver_Remove_ReleasesIPAddress(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.LocalVMID = "localVMID"
+	driver.NetworkID = 7
+	driver.ReservedIPAddress = "10.0.0.20"
+	driver.TenantID = "tenantID"
+	released := false
+	mux.HandleFunc("/networks/7/ips/release", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		released = true
+	})
+
+	err := driver.Remove()
+
+	assert.NoError(t, err)
+	assert.True(t, released)
+	assert.Empty(t, driver.ReservedIPAddress)
+}
+
+func TestDriver_Remove_KeepIP(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.KeepIP = true
+	driver.LocalVMID = "localVMID"
+	driver.NetworkID = 7
+	driver.ReservedIPAddress = "10.0.0.20"
+	driver.TenantID = "tenantID"
+	mux.HandleFunc("/networks/7/ips/release", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("reservation must be kept")
+	})
+
+	err := driver.Remove()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.20", driver.ReservedIPAddress)
+}
@@ -0,0 +1,185 @@
+package xelon
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/docker/machine/libmachine/log"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon/api"
+)
+
+const (
+	ipFamilyIPv4       = "ipv4"
+	ipFamilyIPv6       = "ipv6"
+	ipFamilyPreferIPv6 = "prefer-ipv6"
+
+	ipVisibilityAny     = "any"
+	ipVisibilityPrivate = "private"
+	ipVisibilityPublic  = "public"
+)
+
+// privateNetworks contains the address ranges which are considered private: RFC 1918, shared address
+// space (RFC 6598) and unique local IPv6 addresses (RFC 4193).
+var privateNetworks = mustParseCIDRs("10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "100.64.0.0/10", "fc00::/7")
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+func isPrivateIP(ip net.IP) bool {
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// addressPolicy determines which address of the device is used to connect to it.
+type addressPolicy struct {
+	// Family is one of "ipv4", "ipv6" or "prefer-ipv6". Empty is the same as "ipv4".
+	Family string
+	// Label restricts the addresses to the network interface with this label, e.g. "eth1".
+	Label string
+	// Visibility is one of "any", "private" or "public". Empty is the same as "any".
+	Visibility string
+}
+
+func (p addressPolicy) String() string {
+	family := firstString(p.Family, ipFamilyIPv4)
+	visibility := firstString(p.Visibility, ipVisibilityAny)
+	if p.Label == "" {
+		return fmt.Sprintf("family %v and visibility %v", family, visibility)
+	}
+	return fmt.Sprintf("family %v, label %q and visibility %v", family, p.Label, visibility)
+}
+
+// validateIPFamily checks that family is a supported address family.
+func validateIPFamily(family string) error {
+	switch family {
+	case ipFamilyIPv4, ipFamilyIPv6, ipFamilyPreferIPv6:
+		return nil
+	}
+	return fmt.Errorf("xelon-ip-family must be one of %v, %v or %v", ipFamilyIPv4, ipFamilyIPv6, ipFamilyPreferIPv6)
+}
+
+// validateIPVisibility checks that visibility is a supported address visibility.
+func validateIPVisibility(visibility string) error {
+	switch visibility {
+	case ipVisibilityAny, ipVisibilityPrivate, ipVisibilityPublic:
+		return nil
+	}
+	return fmt.Errorf("xelon-ip-visibility must be one of %v, %v or %v", ipVisibilityAny, ipVisibilityPrivate, ipVisibilityPublic)
+}
+
+// selectAddress returns the first address of the networks which matches the policy. Loopback and
+// link-local addresses are never selected.
+func selectAddress(networks []api.Network, policy addressPolicy) (string, error) {
+	first := make(map[api.AddressFamily]string)
+	for _, network := range networks {
+		if policy.Label != "" && network.Label != policy.Label {
+			continue
+		}
+		for _, address := range network.Addresses() {
+			ip := net.ParseIP(address.Address)
+			if ip.IsUnspecified() || ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+				continue
+			}
+			switch policy.Visibility {
+			case ipVisibilityPrivate:
+				if !isPrivateIP(ip) {
+					continue
+				}
+			case ipVisibilityPublic:
+				if isPrivateIP(ip) {
+					continue
+				}
+			}
+			if first[address.Family] == "" {
+				first[address.Family] = address.Address
+			}
+		}
+	}
+
+	var candidates []string
+	switch policy.Family {
+	case ipFamilyIPv6:
+		candidates = []string{first[api.AddressFamilyIPv6]}
+	case ipFamilyPreferIPv6:
+		candidates = []string{first[api.AddressFamilyIPv6], first[api.AddressFamilyIPv4]}
+	default:
+		candidates = []string{first[api.AddressFamilyIPv4]}
+	}
+	if ip := firstString(candidates...); ip != "" {
+		return ip, nil
+	}
+	return "", fmt.Errorf("no IP address with %v is assigned to Xelon device", policy)
+}
+
+// GetIP returns the public endpoint of the machine if one is set, since docker-machine issues the server
+// certificate of the Docker engine for this address. Otherwise it returns the address of the device.
+func (d *Driver) GetIP() (string, error) {
+	if d.PublicEndpoint != "" {
+		return d.PublicEndpoint, nil
+	}
+	return d.deviceIPAddress()
+}
+
+// deviceIPAddress returns the address of the device which matches the address selection policy. The address
+// is looked up with the Xelon API, so that changed addresses are picked up. Only the address in memory is
+// updated, docker-machine stores it with the machine's configuration after commands which save the machine,
+// since writing the configuration from the driver would race with docker-machine. The stored address is
+// returned if the API is not reachable.
+func (d *Driver) deviceIPAddress() (string, error) {
+	if d.LocalVMID == "" {
+		return d.BaseDriver.GetIP()
+	}
+
+	client, err := d.getClient()
+	if err != nil {
+		return "", err
+	}
+	deviceRoot, _, err := client.Devices.Get(d.TenantID, d.LocalVMID)
+	if err != nil {
+		if d.IPAddress != "" {
+			log.Debugf("Could not look up IP address of Xelon device, using stored address %v: %v", d.IPAddress, err)
+			return d.IPAddress, nil
+		}
+		return "", err
+	}
+
+	ip, err := selectAddress(deviceRoot.Device.Networks, addressPolicy{Family: d.IPFamily, Label: d.NICLabel, Visibility: d.IPVisibility})
+	if err != nil {
+		return "", err
+	}
+	if ip != d.IPAddress {
+		if d.IPAddress != "" {
+			log.Infof("IP address of Xelon device changed from %v to %v", d.IPAddress, ip)
+		}
+		d.IPAddress = ip
+	}
+	return ip, nil
+}
+
+// waitForIPAddress waits until an address which matches the address selection policy is assigned
+// to the device.
+func (d *Driver) waitForIPAddress() error {
+	log.Debug("Waiting until IP address is assigned...")
+	var err error
+	for i := 0; i < int(deviceStateTimeout/deviceStatePollInterval); i++ {
+		if _, err = d.deviceIPAddress(); err == nil {
+			return nil
+		}
+		sleep(deviceStatePollInterval)
+	}
+	return fmt.Errorf("timeout waiting for IP address of Xelon device %v: %v", d.LocalVMID, err)
+}
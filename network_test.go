@@ -0,0 +1,135 @@
+package xelon
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon/api"
+)
+
+func TestSelectAddress(t *testing.T) {
+	networks := []api.Network{
+		{IPAddress: "fe80::1", Label: "eth0"},
+		{IPAddress: "203.0.113.10", IPv6Address: "2001:db8::10", Label: "eth0"},
+		{IPAddress: "fd00::10", Label: "eth1"},
+		{IPAddress: "10.0.0.10", Label: "eth1"},
+	}
+	tests := map[string]struct {
+		policy addressPolicy
+		want   string
+	}{
+		"default":          {addressPolicy{}, "203.0.113.10"},
+		"any":              {addressPolicy{Visibility: ipVisibilityAny}, "203.0.113.10"},
+		"private":          {addressPolicy{Visibility: ipVisibilityPrivate}, "10.0.0.10"},
+		"public":           {addressPolicy{Visibility: ipVisibilityPublic}, "203.0.113.10"},
+		"label":            {addressPolicy{Label: "eth1"}, "10.0.0.10"},
+		"label and public": {addressPolicy{Label: "eth0", Visibility: ipVisibilityPublic}, "203.0.113.10"},
+		"ipv4":             {addressPolicy{Family: ipFamilyIPv4}, "203.0.113.10"},
+		"ipv6":             {addressPolicy{Family: ipFamilyIPv6}, "2001:db8::10"},
+		"ipv6 and private": {addressPolicy{Family: ipFamilyIPv6, Visibility: ipVisibilityPrivate}, "fd00::10"},
+		"ipv6 and label":   {addressPolicy{Family: ipFamilyIPv6, Label: "eth1"}, "fd00::10"},
+		"prefer-ipv6":      {addressPolicy{Family: ipFamilyPreferIPv6}, "2001:db8::10"},
+		"prefer-ipv6 and private": {
+			addressPolicy{Family: ipFamilyPreferIPv6, Visibility: ipVisibilityPrivate}, "fd00::10",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ip, err := selectAddress(networks, test.policy)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, ip)
+		})
+	}
+}
+
+func TestSelectAddress_ipv6Only(t *testing.T) {
+	networks := []api.Network{{IPAddress: "fe80::1"}, {IPAddress: "2001:db8::10"}}
+
+	ip, err := selectAddress(networks, addressPolicy{Family: ipFamilyPreferIPv6})
+	assert.NoError(t, err)
+	assert.Equal(t, "2001:db8::10", ip)
+
+	_, err = selectAddress(networks, addressPolicy{Family: ipFamilyIPv4})
+	assert.Error(t, err)
+}
+
+func TestSelectAddress_preferIPv6Fallback(t *testing.T) {
+	ip, err := selectAddress([]api.Network{{IPAddress: "10.0.0.10"}}, addressPolicy{Family: ipFamilyPreferIPv6})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.10", ip)
+}
+
+func TestSelectAddress_noMatch(t *testing.T) {
+	_, err := selectAddress([]api.Network{{IPAddress: "10.0.0.10", Label: "eth0"}}, addressPolicy{Label: "eth1"})
+	assert.Error(t, err)
+
+	_, err = selectAddress(nil, addressPolicy{})
+	assert.Error(t, err)
+}
+
+func TestDriver_GetIP_Changed(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	writeMachineConfig(t, driver.StorePath, "default", `{"Driver": {"IPAddress": "10.0.0.10"}, "DriverName": "xelon"}`)
+	driver.IPAddress = "10.0.0.10"
+	driver.LocalVMID = "localVMID"
+	driver.TenantID = "tenantID"
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"device":{"networks":[{"ip":"10.0.0.20","label":"eth0"}]}}`)
+	})
+
+	ip, err := driver.GetIP()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.20", ip)
+	assert.Equal(t, "10.0.0.20", driver.IPAddress)
+	// the configuration is saved by docker-machine, not by the driver
+	saved, _ := LoadDriver(driver.StorePath, "default")
+	assert.Equal(t, "10.0.0.10", saved.IPAddress)
+}
+
+func TestDriver_GetIP_APIUnavailable(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.IPAddress = "10.0.0.10"
+	driver.LocalVMID = "localVMID"
+	driver.TenantID = "tenantID"
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	ip, err := driver.GetIP()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.10", ip)
+}
+
+func TestDriver_Create_WaitsForIPAddress(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	mux.HandleFunc("/vmlist/create", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"device":{"localvmid":"localVMID"}}`)
+	})
+	requests := 0
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		networks := `[]`
+		if requests > 3 {
+			networks = `[{"ip":"10.0.0.10","label":"eth0"}]`
+		}
+		_, _ = fmt.Fprintf(w, `{
+			"device":{"localvmdetails":{"state":1},"networks":%v,"powerstate":true},
+			"toolsStatus":{"runningStatus":"guestToolsRunning"}
+		}`, networks)
+	})
+
+	err := driver.Create()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.10", driver.IPAddress)
+}
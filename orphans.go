@@ -0,0 +1,159 @@
+package xelon
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/log"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon/api"
+)
+
+// Reasons why a device is considered orphaned.
+const (
+	OrphanReasonNotInStore = "not in store"
+	OrphanReasonUntagged   = "untagged"
+)
+
+// Orphan represents a device which was created by the driver but has no machine in the local
+// docker-machine store, e.g. after a failed create or a deleted store.
+type Orphan struct {
+	CreatedAt   string   `json:"created_at"`
+	Hostname    string   `json:"hostname"`
+	LocalVMID   string   `json:"localvmid"`
+	MachineName string   `json:"machine_name"`
+	Protected   bool     `json:"protected"`
+	Reason      string   `json:"reason"`
+	SSHKeys     []string `json:"ssh_keys"`
+}
+
+// FindOrphans lists the devices of the tenant which were created by the driver for the store at storePath
+// but have no machine in it. Devices created by drivers without tag support are recognized by an SSH key
+// named after their hostname; since they may belong to any store, they are only included if includeUntagged
+// is set.
+//
+// Devices which may still be created are skipped: devices created less than minAge ago, or whose creation
+// time is unknown if minAge is set, and devices of a machine in the store which doesn't know its device yet.
+// Devices of a machine whose configuration cannot be read are skipped as well.
+func (a *Account) FindOrphans(storePath string, includeUntagged bool, minAge time.Duration) ([]Orphan, error) {
+	client, err := a.client()
+	if err != nil {
+		return nil, err
+	}
+	machines, unreadable, err := loadMachines(storePath)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(machines))
+	// machines which are being created or cannot be read may refer to a device, which is not an orphan then
+	inStore := make(map[string]bool)
+	for _, machine := range machines {
+		if machine.LocalVMID != "" {
+			known[machine.LocalVMID] = true
+		} else {
+			inStore[machine.MachineName] = true
+		}
+	}
+	for _, name := range unreadable {
+		inStore[name] = true
+	}
+	devices, _, err := client.Devices.List(a.TenantID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not list devices: %v", err)
+	}
+
+	storeHash := storePathHash(storePath)
+	current := now()
+	orphans := []Orphan{}
+	for _, device := range devices {
+		if known[device.LocalVMID] {
+			continue
+		}
+		if minAge > 0 {
+			createdAt, err := time.Parse(time.RFC3339, device.CreatedAt)
+			if err != nil {
+				log.Debugf("Device %v has an unknown creation time %q, skipping it", device.LocalVMID, device.CreatedAt)
+				continue
+			}
+			if current.Sub(createdAt) < minAge {
+				log.Debugf("Device %v was created less than %v ago, skipping it", device.LocalVMID, minAge)
+				continue
+			}
+		}
+
+		orphan := Orphan{
+			CreatedAt: device.CreatedAt,
+			Hostname:  device.VMHostname,
+			LocalVMID: device.LocalVMID,
+			Protected: device.Tags[tagDeleteProtection] == "true",
+			SSHKeys:   []string{},
+		}
+		for _, key := range device.SSHKeys {
+			orphan.SSHKeys = append(orphan.SSHKeys, key.Name)
+		}
+
+		switch {
+		case device.Tags[tagManaged] == "true":
+			if device.Tags[tagStorePathHash] != storeHash {
+				continue
+			}
+			orphan.MachineName = device.Tags[tagMachineName]
+			orphan.Reason = OrphanReasonNotInStore
+		case includeUntagged && hasSSHKey(device.SSHKeys, device.VMHostname):
+			orphan.MachineName = device.VMHostname
+			orphan.Reason = OrphanReasonUntagged
+		default:
+			continue
+		}
+		if inStore[orphan.MachineName] {
+			log.Debugf("Machine %v of device %v is being created or cannot be read, skipping it", orphan.MachineName, device.LocalVMID)
+			continue
+		}
+		orphans = append(orphans, orphan)
+	}
+
+	sort.Slice(orphans, func(i, j int) bool { return orphans[i].MachineName < orphans[j].MachineName })
+	return orphans, nil
+}
+
+func hasSSHKey(keys []api.SSHKey, name string) bool {
+	for _, key := range keys {
+		if name != "" && key.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteOrphan stops and deletes an orphaned device together with the firewall rules which the driver
+// created for it.
+func (a *Account) DeleteOrphan(orphan Orphan) error {
+	client, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	d, err := a.deviceDriver(client, orphan.MachineName, orphan.LocalVMID)
+	if err != nil {
+		return err
+	}
+	return d.Remove()
+}
+
+// deviceDriver returns a driver for a device without machine in the store, which knows the firewall rules
+// that the driver created for the device.
+func (a *Account) deviceDriver(client *api.Client, machineName, localVMID string) (*Driver, error) {
+	d := a.driver(machineName, localVMID)
+	rules, _, err := client.Firewalls.List(a.TenantID, &api.FirewallRuleListOptions{LocalVMID: localVMID})
+	if err != nil {
+		return nil, fmt.Errorf("could not list firewall rules: %v", err)
+	}
+	for _, rule := range rules {
+		if strings.HasPrefix(rule.Name, firewallRuleNamePrefix(machineName)) {
+			d.FirewallRuleIDs = append(d.FirewallRuleIDs, rule.ID)
+		}
+	}
+	return d, nil
+}
@@ -0,0 +1,136 @@
+package xelon
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupOrphans(t *testing.T) (account *Account, storePath string, mux *http.ServeMux, teardown func()) {
+	driver, mux, teardown := setup("ci-runner-1")
+	writeMachineConfig(t, driver.StorePath, "ci-runner-1", `{
+		"Driver": {"LocalVMID": "known", "MachineName": "ci-runner-1"},
+		"DriverName": "xelon"
+	}`)
+	storeHash := storePathHash(driver.StorePath)
+	mux.HandleFunc("/vmlist", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `[
+			{"localvmid":"known","vmhostname":"ci-runner-1","tags":{"docker-machine":"true","docker-machine-store":%[1]q}},
+			{"localvmid":"failed","vmhostname":"ci-runner-2","created_at":"2026-10-01T00:00:00Z",
+			 "ssh_keys":[{"id":1,"name":"ci-runner-2"}],
+			 "tags":{"docker-machine":"true","docker-machine-name":"ci-runner-2","docker-machine-store":%[1]q}},
+			{"localvmid":"other-store","vmhostname":"ci-runner-3","tags":{"docker-machine":"true","docker-machine-store":"other"}},
+			{"localvmid":"legacy","vmhostname":"ci-runner-4","ssh_keys":[{"id":2,"name":"ci-runner-4"}]},
+			{"localvmid":"manual","vmhostname":"database","ssh_keys":[{"id":3,"name":"admin"}]}
+		]`, storeHash)
+	})
+
+	account = &Account{APIBaseURL: driver.APIBaseURL, TenantID: "tenantID", Token: "token"}
+	return account, driver.StorePath, mux, teardown
+}
+
+func TestAccount_FindOrphans(t *testing.T) {
+	account, storePath, _, teardown := setupOrphans(t)
+	defer teardown()
+
+	orphans, err := account.FindOrphans(storePath, false, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Orphan{{
+		CreatedAt:   "2026-10-01T00:00:00Z",
+		Hostname:    "ci-runner-2",
+		LocalVMID:   "failed",
+		MachineName: "ci-runner-2",
+		Reason:      OrphanReasonNotInStore,
+		SSHKeys:     []string{"ci-runner-2"},
+	}}, orphans)
+}
+
+func TestAccount_FindOrphans_untagged(t *testing.T) {
+	account, storePath, _, teardown := setupOrphans(t)
+	defer teardown()
+
+	orphans, err := account.FindOrphans(storePath, true, 0)
+
+	assert.NoError(t, err)
+	assert.Len(t, orphans, 2)
+	assert.Equal(t, "legacy", orphans[1].LocalVMID)
+	assert.Equal(t, OrphanReasonUntagged, orphans[1].Reason)
+}
+
+func TestAccount_FindOrphans_minAge(t *testing.T) {
+	account, storePath, _, teardown := setupOrphans(t)
+	defer teardown()
+	defer func() { now = time.Now }()
+
+	now = func() time.Time { return time.Date(2026, 10, 1, 0, 30, 0, 0, time.UTC) }
+	orphans, err := account.FindOrphans(storePath, true, time.Hour)
+
+	assert.NoError(t, err)
+	assert.Empty(t, orphans, "recent devices and devices without creation time must be skipped")
+
+	now = func() time.Time { return time.Date(2026, 10, 1, 1, 0, 0, 0, time.UTC) }
+	orphans, err = account.FindOrphans(storePath, true, time.Hour)
+
+	assert.NoError(t, err)
+	assert.Len(t, orphans, 1)
+	assert.Equal(t, "failed", orphans[0].LocalVMID)
+}
+
+func TestAccount_FindOrphans_machineBeingCreated(t *testing.T) {
+	account, storePath, _, teardown := setupOrphans(t)
+	defer teardown()
+	writeMachineConfig(t, storePath, "ci-runner-2", `{
+		"Driver": {"MachineName": "ci-runner-2"},
+		"DriverName": "xelon"
+	}`)
+
+	orphans, err := account.FindOrphans(storePath, false, 0)
+
+	assert.NoError(t, err)
+	assert.Empty(t, orphans)
+}
+
+func TestAccount_FindOrphans_unreadableMachine(t *testing.T) {
+	account, storePath, _, teardown := setupOrphans(t)
+	defer teardown()
+	writeMachineConfig(t, storePath, "ci-runner-2", `{"Driver": {"LocalVMID": "failed"`)
+	writeMachineConfig(t, storePath, "ci-runner-4", `{"Driver": {}, "DriverName": "virtualbox"}`)
+
+	orphans, err := account.FindOrphans(storePath, true, 0)
+
+	assert.NoError(t, err)
+	assert.Len(t, orphans, 1, "only the device of the unreadable machine must be skipped")
+	assert.Equal(t, "legacy", orphans[0].LocalVMID)
+}
+
+func TestAccount_DeleteOrphan(t *testing.T) {
+	account, _, mux, teardown := setupOrphans(t)
+	defer teardown()
+	mux.HandleFunc("/firewalls", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "failed", r.URL.Query().Get("localvmid"))
+		_, _ = fmt.Fprint(w, `[
+			{"id":7,"name":"docker-machine-ci-runner-2-22"},
+			{"id":8,"name":"allow-monitoring"}
+		]`)
+	})
+	var deleted []string
+	mux.HandleFunc("/firewalls/", func(w http.ResponseWriter, r *http.Request) {
+		deleted = append(deleted, r.Method+" "+r.URL.Path)
+	})
+	mux.HandleFunc("/vmlist/failed/stopserver", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/vmlist/failed", func(w http.ResponseWriter, r *http.Request) {
+		deleted = append(deleted, r.Method+" "+r.URL.Path)
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"device":{"powerstate":false}}`)
+	})
+
+	err := account.DeleteOrphan(Orphan{LocalVMID: "failed", MachineName: "ci-runner-2"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"DELETE /firewalls/7", "DELETE /vmlist/failed"}, deleted)
+}
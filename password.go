@@ -0,0 +1,116 @@
+package xelon
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+const (
+	defaultPasswordMinCharacterClasses = 3
+	defaultPasswordMinLength           = 12
+	generatedPasswordLength            = 24
+)
+
+// passwordCharacterClasses are the character sets used to generate device passwords. Symbols are limited
+// to characters which are safe to use in shells and configuration files.
+var passwordCharacterClasses = []string{
+	"abcdefghijkmnopqrstuvwxyz",
+	"ABCDEFGHJKLMNPQRSTUVWXYZ",
+	"23456789",
+	"!#%+-.=@_",
+}
+
+// generatePassword returns a random password of the given length which contains characters of all
+// password character classes.
+func generatePassword(length int) (string, error) {
+	if length < len(passwordCharacterClasses) {
+		length = len(passwordCharacterClasses)
+	}
+
+	password := make([]byte, 0, length)
+	for _, class := range passwordCharacterClasses {
+		c, err := randomChar(class)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+	allChars := strings.Join(passwordCharacterClasses, "")
+	for len(password) < length {
+		c, err := randomChar(allChars)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+
+	// shuffle password, so the character classes are not always at the beginning
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+
+	return string(password), nil
+}
+
+func randomChar(chars string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+	if err != nil {
+		return 0, err
+	}
+	return chars[n.Int64()], nil
+}
+
+// readPasswordFile reads the device password from the first line of a file.
+func readPasswordFile(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("could not read device password file: %v", err)
+	}
+	password := strings.TrimRight(strings.SplitN(string(data), "\n", 2)[0], "\r")
+	if password == "" {
+		return "", fmt.Errorf("device password file %v is empty", path)
+	}
+	return password, nil
+}
+
+// validatePassword checks that password satisfies the complexity policy given by the minimal length and
+// the minimal number of character classes (lowercase letters, uppercase letters, digits and symbols).
+func validatePassword(password string, minLength, minCharacterClasses int) error {
+	if len(password) < minLength {
+		return fmt.Errorf("xelon-device-password must be at least %d characters long", minLength)
+	}
+
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	classes := 0
+	for _, present := range []bool{lower, upper, digit, symbol} {
+		if present {
+			classes++
+		}
+	}
+	if classes < minCharacterClasses {
+		return fmt.Errorf("xelon-device-password must contain at least %d of the following character classes: "+
+			"lowercase letters, uppercase letters, digits and symbols", minCharacterClasses)
+	}
+
+	return nil
+}
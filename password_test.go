@@ -0,0 +1,57 @@
+package xelon
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratePassword(t *testing.T) {
+	password, err := generatePassword(generatedPasswordLength)
+
+	assert.NoError(t, err)
+	assert.Len(t, password, generatedPasswordLength)
+	assert.NoError(t, validatePassword(password, generatedPasswordLength, 4))
+}
+
+func TestGeneratePassword_unique(t *testing.T) {
+	first, _ := generatePassword(generatedPasswordLength)
+	second, _ := generatePassword(generatedPasswordLength)
+
+	assert.NotEqual(t, first, second)
+}
+
+func TestValidatePassword(t *testing.T) {
+	assert.Error(t, validatePassword("Xelon22", 12, 3))
+	assert.Error(t, validatePassword("xelonxelonxelon", 12, 3))
+	assert.Error(t, validatePassword("xelonxelon22", 12, 3))
+	assert.NoError(t, validatePassword("Xelonxelon22", 12, 3))
+	assert.NoError(t, validatePassword("xelon-xelon-22", 12, 3))
+	assert.NoError(t, validatePassword("xelonxelon22", 12, 2))
+}
+
+func TestReadPasswordFile(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "xelon")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "password")
+	_ = ioutil.WriteFile(path, []byte("Secret-Password-1\r\n"), 0600)
+
+	password, err := readPasswordFile(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Secret-Password-1", password)
+}
+
+func TestReadPasswordFile_empty(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "xelon")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "password")
+	_ = ioutil.WriteFile(path, []byte("\n"), 0600)
+
+	_, err := readPasswordFile(path)
+
+	assert.Error(t, err)
+}
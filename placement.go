@@ -0,0 +1,147 @@
+package xelon
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/docker/machine/libmachine/log"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon/api"
+)
+
+// placements records the hypervisor systems which were selected for the machines of an anti-affinity group
+// by a process creating several machines at once. A device is only listed with its system once it has been
+// created, so placements are serialized and the systems which were selected are taken into account.
+type placements struct {
+	mu   sync.Mutex
+	used map[string]map[int][]string
+}
+
+func newPlacements() *placements {
+	return &placements{used: make(map[string]map[int][]string)}
+}
+
+func (p *placements) add(group string, hvSystemID int, machineName string) {
+	if p.used[group] == nil {
+		p.used[group] = make(map[int][]string)
+	}
+	p.used[group][hvSystemID] = append(p.used[group][hvSystemID], machineName)
+}
+
+// antiAffinityGroupUsage returns the names of the other machines of the anti-affinity group of the driver by
+// the hypervisor system they are placed on. Devices are found by the anti-affinity group tag, machines which
+// were created before the group was tagged are found in the docker-machine store.
+func (d *Driver) antiAffinityGroupUsage(client *api.Client) (map[int][]string, error) {
+	usedBy := make(map[int][]string)
+	seen := make(map[string]bool)
+	tagged, _, err := client.Devices.List(d.TenantID, &api.DeviceListOptions{Tags: map[string]string{tagAntiAffinityGroup: d.AntiAffinityGroup}})
+	if err != nil {
+		return nil, fmt.Errorf("could not list devices of anti-affinity group %v: %v", d.AntiAffinityGroup, err)
+	}
+	for _, device := range tagged {
+		name := device.Tags[tagMachineName]
+		if name == "" {
+			name = device.VMHostname
+		}
+		seen[device.LocalVMID] = true
+		if name != d.MachineName && device.HVSystemID != 0 {
+			usedBy[device.HVSystemID] = append(usedBy[device.HVSystemID], name)
+		}
+	}
+
+	machines, _, err := loadMachines(d.StorePath)
+	if err != nil {
+		return nil, err
+	}
+	var members []*Driver
+	for _, machine := range machines {
+		if machine.MachineName != d.MachineName && machine.AntiAffinityGroup == d.AntiAffinityGroup && machine.LocalVMID != "" && !seen[machine.LocalVMID] {
+			members = append(members, machine)
+		}
+	}
+	if len(members) == 0 {
+		return usedBy, nil
+	}
+	devices, _, err := client.Devices.List(d.TenantID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not list devices: %v", err)
+	}
+	hvSystemIDs := make(map[string]int, len(devices))
+	for _, device := range devices {
+		hvSystemIDs[device.LocalVMID] = device.HVSystemID
+	}
+	for _, member := range members {
+		if hvSystemID, ok := hvSystemIDs[member.LocalVMID]; ok && hvSystemID != 0 {
+			usedBy[hvSystemID] = append(usedBy[hvSystemID], member.MachineName)
+		}
+	}
+	return usedBy, nil
+}
+
+// placeDevice selects the hypervisor system of the device, so that it doesn't share a system with another
+// machine of its anti-affinity group. An explicitly given system is only checked. It is an error if no
+// system is left for the device.
+func (d *Driver) placeDevice(client *api.Client) error {
+	if d.placements != nil {
+		d.placements.mu.Lock()
+		defer d.placements.mu.Unlock()
+	}
+	usedBy, err := d.antiAffinityGroupUsage(client)
+	if err != nil {
+		return err
+	}
+	if d.placements != nil {
+		for hvSystemID, machines := range d.placements.used[d.AntiAffinityGroup] {
+			usedBy[hvSystemID] = append(usedBy[hvSystemID], machines...)
+		}
+	}
+
+	if d.HVSystemID != 0 {
+		if machines := usedBy[d.HVSystemID]; len(machines) > 0 {
+			return fmt.Errorf("xelon-hv-system-id %d is already used by %v of anti-affinity group %q",
+				d.HVSystemID, strings.Join(machines, ", "), d.AntiAffinityGroup)
+		}
+		d.recordPlacement()
+		return nil
+	}
+
+	systems, _, err := client.Hypervisors.List(d.TenantID)
+	if err != nil {
+		return fmt.Errorf("could not list hypervisor systems: %v", err)
+	}
+	sort.Slice(systems, func(i, j int) bool { return systems[i].ID < systems[j].ID })
+	for _, system := range systems {
+		if len(usedBy[system.ID]) == 0 {
+			log.Debugf("Placing device on hypervisor system %v (%d)", system.Name, system.ID)
+			d.HVSystemID = system.ID
+			d.recordPlacement()
+			return nil
+		}
+	}
+	return fmt.Errorf("could not place machine %v: all %d hypervisor systems are used by machines of anti-affinity group %q",
+		d.MachineName, len(systems), d.AntiAffinityGroup)
+}
+
+func (d *Driver) recordPlacement() {
+	if d.placements != nil {
+		d.placements.add(d.AntiAffinityGroup, d.HVSystemID, d.MachineName)
+	}
+}
+
+// deleteMisplacedDevice deletes a device which was placed on another hypervisor system than requested, and
+// releases its IP address reservation. The device is kept in the machine if it cannot be deleted, so it can
+// be removed later.
+func (d *Driver) deleteMisplacedDevice(client *api.Client) error {
+	log.Infof("Deleting Xelon device %v placed on the wrong hypervisor system...", d.LocalVMID)
+	if err := d.stopDevice(); err != nil {
+		return fmt.Errorf("could not stop misplaced device %v: %v", d.LocalVMID, err)
+	}
+	if resp, err := client.Devices.Delete(d.LocalVMID); err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return fmt.Errorf("could not delete misplaced device %v: %v", d.LocalVMID, err)
+	}
+	d.LocalVMID = ""
+	return d.releaseIPAddress(client)
+}
@@ -0,0 +1,163 @@
+package xelon
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testHypervisorSystems = `[{"id":3,"name":"hv-3"},{"id":1,"name":"hv-1"},{"id":2,"name":"hv-2"}]`
+
+// setupAntiAffinityGroup stores the machine manager-1 of the anti-affinity group "managers" on the hypervisor
+// system 1 and the machine worker-1 of another group on system 3. The device manager-2 of another store is
+// tagged with the group and placed on system 2.
+func setupAntiAffinityGroup(t *testing.T, driver *Driver, mux *http.ServeMux) {
+	for name, group := range map[string]string{"manager-1": "managers", "worker-1": "workers"} {
+		writeMachineConfig(t, driver.StorePath, name, fmt.Sprintf(`{
+			"Driver": {"AntiAffinityGroup": %q, "LocalVMID": %q, "MachineName": %q},
+			"DriverName": "xelon"
+		}`, group, name, name))
+	}
+	writeMachineConfig(t, driver.StorePath, "other-driver", `{"Driver": {}, "DriverName": "virtualbox"}`)
+	mux.HandleFunc("/vmlist", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("tags[docker-machine-anti-affinity-group]") == "managers" {
+			_, _ = fmt.Fprint(w, `[
+				{"localvmid":"manager-2","hv_system_id":2,"tags":{"docker-machine-anti-affinity-group":"managers","docker-machine-name":"manager-2"}}
+			]`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `[
+			{"localvmid":"manager-1","hv_system_id":1},
+			{"localvmid":"manager-2","hv_system_id":2},
+			{"localvmid":"worker-1","hv_system_id":3}
+		]`)
+	})
+	mux.HandleFunc("/hypervisors", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, testHypervisorSystems)
+	})
+}
+
+func TestDriver_Create_AntiAffinityGroup(t *testing.T) {
+	driver, mux, teardown := setup("manager-3")
+	defer teardown()
+	driver.AntiAffinityGroup = "managers"
+	setupAntiAffinityGroup(t, driver, mux)
+	mux.HandleFunc("/vmlist/create", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "3", r.URL.Query().Get("hv_system_id"))
+		assert.Equal(t, "managers", r.URL.Query().Get("tags[docker-machine-anti-affinity-group]"))
+		_, _ = fmt.Fprint(w, `{"device":{"localvmid":"localVMID"}}`)
+	})
+
+	err := driver.Create()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, driver.HVSystemID)
+}
+
+func TestDriver_Create_AntiAffinityGroupFull(t *testing.T) {
+	driver, mux, teardown := setup("manager-3")
+	defer teardown()
+	driver.AntiAffinityGroup = "managers"
+	setupAntiAffinityGroup(t, driver, mux)
+	writeMachineConfig(t, driver.StorePath, "manager-4", `{
+		"Driver": {"AntiAffinityGroup": "managers", "LocalVMID": "worker-1", "MachineName": "manager-4"},
+		"DriverName": "xelon"
+	}`)
+	mux.HandleFunc("/vmlist/create", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("device must not be created")
+	})
+
+	err := driver.Create()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `all 3 hypervisor systems are used by machines of anti-affinity group "managers"`)
+}
+
+func TestDriver_Create_AntiAffinityGroupExplicitSystem(t *testing.T) {
+	driver, mux, teardown := setup("manager-3")
+	defer teardown()
+	driver.AntiAffinityGroup = "managers"
+	driver.HVSystemID = 2
+	setupAntiAffinityGroup(t, driver, mux)
+
+	err := driver.Create()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "xelon-hv-system-id 2 is already used by manager-2")
+}
+
+func TestBulkCreate_AntiAffinityGroup(t *testing.T) {
+	storePath, mux, configure, teardown := setupBulkCreate()
+	defer teardown()
+	defer func(original func(string, string) error) { provisionMachine = original }(provisionMachine)
+	provisionMachine = func(storePath, machineName string) error { return nil }
+	mux.HandleFunc("/hypervisors", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, testHypervisorSystems)
+	})
+	var mu sync.Mutex
+	var hvSystemIDs []string
+	mux.HandleFunc("/vmlist/create", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		hvSystemIDs = append(hvSystemIDs, r.URL.Query().Get("hv_system_id"))
+		_, _ = fmt.Fprint(w, `{"device":{"localvmid":"localVMID"}}`)
+	})
+
+	results, err := BulkCreate(storePath, []string{"manager-1", "manager-2", "manager-3"}, 3, false, func(d *Driver) error {
+		d.AntiAffinityGroup = "managers"
+		return configure(d)
+	})
+
+	assert.NoError(t, err)
+	for _, result := range results {
+		assert.NoError(t, result.Err)
+	}
+	assert.ElementsMatch(t, []string{"1", "2", "3"}, hvSystemIDs)
+}
+
+func TestDriver_Create_HVSystemMismatch(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.HVSystemID = 2
+	driver.NetworkID = 7
+	driver.StaticIPAddress = "10.0.0.20"
+	var mu sync.Mutex
+	powerstate := true
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = fmt.Fprintf(w, `{
+			"device":{"localvmdetails":{"hv_system_id":1,"state":1},"powerstate":%v},
+			"toolsStatus":{"runningStatus":"guestToolsRunning"}
+		}`, powerstate)
+	})
+	mux.HandleFunc("/vmlist/localVMID/stopserver", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		powerstate = false
+	})
+	deleted := false
+	mux.HandleFunc("/vmlist/localVMID", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		deleted = true
+	})
+	mux.HandleFunc("/networks/7/ips/reserve", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"ip":"10.0.0.20","reserved":true}`)
+	})
+	released := false
+	mux.HandleFunc("/networks/7/ips/release", func(w http.ResponseWriter, r *http.Request) {
+		released = true
+	})
+
+	err := driver.Create()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "hypervisor system 1 instead of 2")
+	assert.True(t, deleted)
+	assert.True(t, released)
+	assert.Empty(t, driver.LocalVMID)
+	assert.Empty(t, driver.ReservedIPAddress)
+}
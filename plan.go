@@ -0,0 +1,79 @@
+package xelon
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// plan represents a named set of device resources. Resources which are not set in a custom plan
+// fall back to the profile and default values.
+type plan struct {
+	CPUCores     int `yaml:"cpu_cores" json:"cpu_cores"`
+	DiskSize     int `yaml:"disk_size" json:"disk_size"`
+	Memory       int `yaml:"memory" json:"memory"`
+	SwapDiskSize int `yaml:"swap_disk_size" json:"swap_disk_size"`
+}
+
+// planCatalog represents a user-supplied YAML or JSON file with custom plans.
+type planCatalog struct {
+	Plans map[string]plan `yaml:"plans" json:"plans"`
+}
+
+var builtinPlans = map[string]plan{
+	"small":    {CPUCores: 1, Memory: 2, DiskSize: 20, SwapDiskSize: 2},
+	"medium":   {CPUCores: 2, Memory: 4, DiskSize: 40, SwapDiskSize: 2},
+	"large":    {CPUCores: 4, Memory: 8, DiskSize: 80, SwapDiskSize: 4},
+	"large-ci": {CPUCores: 8, Memory: 16, DiskSize: 100, SwapDiskSize: 4},
+}
+
+// loadPlanCatalog reads custom plans from a YAML or JSON file.
+func loadPlanCatalog(path string) (map[string]plan, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read plan catalog: %v", err)
+	}
+
+	catalog := new(planCatalog)
+	if err := yaml.UnmarshalStrict(data, catalog); err != nil {
+		return nil, fmt.Errorf("could not parse plan catalog %v: %v", path, err)
+	}
+
+	return catalog.Plans, nil
+}
+
+// resolvePlan looks up the plan with the given name in the custom plan catalog and then in the built-in
+// plans. An empty name results in an empty plan.
+func resolvePlan(name, catalogPath string) (*plan, error) {
+	plans := make(map[string]plan, len(builtinPlans))
+	for n, p := range builtinPlans {
+		plans[n] = p
+	}
+	if catalogPath != "" {
+		customPlans, err := loadPlanCatalog(catalogPath)
+		if err != nil {
+			return nil, err
+		}
+		for n, p := range customPlans {
+			plans[n] = p
+		}
+	}
+
+	if name == "" {
+		return &plan{}, nil
+	}
+
+	p, ok := plans[name]
+	if !ok {
+		names := make([]string, 0, len(plans))
+		for n := range plans {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("xelon plan %q not found, available plans: %v", name, strings.Join(names, ", "))
+	}
+	return &p, nil
+}
@@ -0,0 +1,77 @@
+package xelon
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writePlanCatalog(t *testing.T, name, content string) (path string, teardown func()) {
+	dir, err := ioutil.TempDir("", "xelon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path = filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path, func() { _ = os.RemoveAll(dir) }
+}
+
+func TestResolvePlan_builtin(t *testing.T) {
+	p, err := resolvePlan("large-ci", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &plan{CPUCores: 8, Memory: 16, DiskSize: 100, SwapDiskSize: 4}, p)
+}
+
+func TestResolvePlan_empty(t *testing.T) {
+	p, err := resolvePlan("", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &plan{}, p)
+}
+
+func TestResolvePlan_notFound(t *testing.T) {
+	_, err := resolvePlan("huge", "")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "large-ci")
+}
+
+func TestResolvePlan_customYAML(t *testing.T) {
+	path, teardown := writePlanCatalog(t, "plans.yaml", `
+plans:
+  build:
+    cpu_cores: 16
+    memory: 64
+  small:
+    cpu_cores: 1
+    memory: 1
+    disk_size: 10
+    swap_disk_size: 1
+`)
+	defer teardown()
+
+	build, err := resolvePlan("build", path)
+	assert.NoError(t, err)
+	assert.Equal(t, &plan{CPUCores: 16, Memory: 64}, build)
+
+	// custom plans override built-in plans with the same name
+	small, err := resolvePlan("small", path)
+	assert.NoError(t, err)
+	assert.Equal(t, &plan{CPUCores: 1, Memory: 1, DiskSize: 10, SwapDiskSize: 1}, small)
+}
+
+func TestResolvePlan_customJSON(t *testing.T) {
+	path, teardown := writePlanCatalog(t, "plans.json", `{"plans": {"build": {"cpu_cores": 16, "memory": 64, "disk_size": 200, "swap_disk_size": 8}}}`)
+	defer teardown()
+
+	p, err := resolvePlan("build", path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &plan{CPUCores: 16, Memory: 64, DiskSize: 200, SwapDiskSize: 8}, p)
+}
@@ -0,0 +1,325 @@
+package xelon
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/log"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon/api"
+)
+
+const defaultPool = "default"
+
+// States of a pool device. Only ready devices, which are provisioned and stopped, can be claimed.
+const (
+	poolStateProvisioning = "provisioning"
+	poolStateReady        = "ready"
+)
+
+// poolProvisioningTimeout is the time after which a pool device which is still provisioning is considered
+// stale, e.g. because the sync which created it failed, and is deleted.
+const poolProvisioningTimeout = time.Hour
+
+// PoolSyncResult represents the devices which were created and deleted by SyncPool.
+type PoolSyncResult struct {
+	Created []string
+	Deleted []string
+	Size    int
+}
+
+// validatePoolOptions checks that the pool name can be used in hostnames and that the options which
+// determine how a device is created are not combined with xelon-use-pool, since a claimed device has
+// already been created.
+func (d *Driver) validatePoolOptions() error {
+	if err := validateHostname(poolDeviceHostname(d.Pool, "00000000")); err != nil {
+		return fmt.Errorf("invalid xelon-pool %q: %v", d.Pool, err)
+	}
+	if !d.UsePool {
+		return nil
+	}
+	for option, given := range map[string]bool{
+		"xelon-anti-affinity-group": d.AntiAffinityGroup != "",
+		"xelon-dry-run":             d.DryRun,
+		"xelon-hv-system-id":        d.HVSystemID != 0,
+		"xelon-ip-address":          d.StaticIPAddress != "",
+	} {
+		if given {
+			return fmt.Errorf("--xelon-use-pool and --%v cannot be used together", option)
+		}
+	}
+	return nil
+}
+
+// poolTags returns the tags of a device in the pool with the given state.
+func poolTags(pool, state string) map[string]string {
+	return map[string]string{
+		tagDriverVersion: Version,
+		tagPool:          pool,
+		tagPoolState:     state,
+	}
+}
+
+func poolDeviceHostname(pool, suffix string) string {
+	return fmt.Sprintf("%v-pool-%v", pool, suffix)
+}
+
+// listPoolDevices returns the devices of the pool, oldest first.
+func (d *Driver) listPoolDevices(client *api.Client) ([]api.LocalVMDetails, error) {
+	devices, _, err := client.Devices.List(d.TenantID, &api.DeviceListOptions{Tags: map[string]string{tagPool: d.Pool}})
+	if err != nil {
+		return nil, fmt.Errorf("could not list devices of pool %v: %v", d.Pool, err)
+	}
+	sort.SliceStable(devices, func(i, j int) bool { return devices[i].CreatedAt < devices[j].CreatedAt })
+	return devices, nil
+}
+
+// claimPoolDevice claims the oldest ready device of the pool for the machine by replacing its pool tags with
+// the tags of the machine. The tags are only replaced if they are unchanged, so a device cannot be claimed
+// twice by concurrent creates. It returns false if the pool has no device left.
+func (d *Driver) claimPoolDevice(client *api.Client) (bool, error) {
+	devices, err := d.listPoolDevices(client)
+	if err != nil {
+		return false, err
+	}
+	for _, device := range devices {
+		if device.Tags[tagPoolState] != poolStateReady {
+			continue
+		}
+		resp, err := client.Devices.CompareAndSwapTags(device.LocalVMID, device.Tags, d.deviceTags())
+		if err != nil {
+			if resp != nil && resp.StatusCode == http.StatusConflict {
+				log.Debugf("Pool device %v was claimed by someone else", device.LocalVMID)
+				continue
+			}
+			return false, fmt.Errorf("could not claim pool device %v: %v", device.LocalVMID, err)
+		}
+		d.LocalVMID = device.LocalVMID
+		return true, nil
+	}
+	return false, nil
+}
+
+// takeOverPoolDevice renames a claimed pool device after the machine and starts it. The device keeps the
+// resources which it was created with.
+func (d *Driver) takeOverPoolDevice(client *api.Client) error {
+	log.Infof("Claimed pool device %v, renaming it to %v...", d.LocalVMID, d.MachineName)
+	if _, err := client.Devices.Rename(d.LocalVMID, &api.DeviceRenameRequest{DisplayName: d.MachineName, Hostname: d.MachineName}); err != nil {
+		return err
+	}
+
+	log.Info("Starting Xelon device...")
+	if err := d.startDevice(); err != nil {
+		return err
+	}
+	if err := d.waitForDeviceRunning(); err != nil {
+		return err
+	}
+	return d.adoptDeviceResources(client)
+}
+
+// SyncPool creates or deletes devices of the pool given by xelon-pool until it has size devices. New devices
+// are created with the options of the driver, and stopped and marked as ready once they are provisioned. New
+// devices which cannot be provisioned are deleted again, as are devices which are still provisioning after
+// poolProvisioningTimeout. Surplus devices are deleted oldest first, devices which are still provisioned are
+// not deleted.
+func (d *Driver) SyncPool(size int) (*PoolSyncResult, error) {
+	if size < 0 {
+		return nil, fmt.Errorf("size must not be negative")
+	}
+	if d.DryRun {
+		return nil, fmt.Errorf("xelon-dry-run cannot be used to sync a pool")
+	}
+	client, err := d.getClient()
+	if err != nil {
+		return nil, err
+	}
+	if d.TenantID == "" {
+		tenant, _, err := client.Tenant.Get()
+		if err != nil {
+			return nil, err
+		}
+		d.TenantID = tenant.TenantIdentifier
+	}
+	if d.CloneFrom != "" {
+		if err := d.resolveCloneSource(client); err != nil {
+			return nil, err
+		}
+	}
+	devices, err := d.listPoolDevices(client)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PoolSyncResult{}
+	var pooled []api.LocalVMDetails
+	for _, device := range devices {
+		if !isStalePoolDevice(device) {
+			pooled = append(pooled, device)
+			continue
+		}
+		log.Infof("Deleting pool device %v, which is still provisioning after %v...", device.VMHostname, poolProvisioningTimeout)
+		deleted, err := d.deletePoolDevice(client, device)
+		if err != nil {
+			return result, err
+		}
+		if deleted {
+			result.Deleted = append(result.Deleted, device.LocalVMID)
+		}
+	}
+	result.Size = len(pooled)
+
+	var created []*Driver
+	for i := len(pooled); i < size; i++ {
+		member, err := d.createPoolDevice()
+		if err != nil {
+			return result, err
+		}
+		created = append(created, member)
+		result.Created = append(result.Created, member.LocalVMID)
+		result.Size++
+	}
+	var provisionErr error
+	for _, member := range created {
+		log.Infof("Waiting until pool device %v is provisioned...", member.MachineName)
+		if err := member.readyPoolDevice(client); err != nil {
+			log.Warnf("Pool device %v could not be provisioned, deleting it: %v", member.MachineName, err)
+			if removeErr := member.Remove(); removeErr != nil {
+				log.Warnf("Could not delete pool device %v: %v", member.LocalVMID, removeErr)
+			} else {
+				result.Deleted = append(result.Deleted, member.LocalVMID)
+			}
+			result.Size--
+			if provisionErr == nil {
+				provisionErr = err
+			}
+		}
+	}
+	if provisionErr != nil {
+		return result, provisionErr
+	}
+
+	for _, device := range pooled {
+		if result.Size <= size {
+			break
+		}
+		if device.Tags[tagPoolState] != poolStateReady {
+			continue
+		}
+		log.Infof("Deleting pool device %v...", device.VMHostname)
+		deleted, err := d.deletePoolDevice(client, device)
+		if err != nil {
+			return result, err
+		}
+		if deleted {
+			result.Deleted = append(result.Deleted, device.LocalVMID)
+		}
+		result.Size--
+	}
+	return result, nil
+}
+
+// isStalePoolDevice reports whether the device is still provisioning after poolProvisioningTimeout. A device
+// whose creation time is unknown is not considered stale.
+func isStalePoolDevice(device api.LocalVMDetails) bool {
+	if device.Tags[tagPoolState] != poolStateProvisioning {
+		return false
+	}
+	createdAt, err := time.Parse(time.RFC3339, device.CreatedAt)
+	if err != nil {
+		return false
+	}
+	return now().Sub(createdAt) >= poolProvisioningTimeout
+}
+
+// readyPoolDevice waits until a new pool device is provisioned, stops it and marks it as ready.
+func (d *Driver) readyPoolDevice(client *api.Client) error {
+	if err := d.waitForDeviceRunning(); err != nil {
+		return err
+	}
+	if err := d.stopDevice(); err != nil {
+		return err
+	}
+	if _, err := client.Devices.CompareAndSwapTags(d.LocalVMID, poolTags(d.Pool, poolStateProvisioning), poolTags(d.Pool, poolStateReady)); err != nil {
+		return fmt.Errorf("could not mark pool device %v as ready: %v", d.LocalVMID, err)
+	}
+	return nil
+}
+
+// deletePoolDevice deletes a device of the pool. The device is claimed before it is deleted, so it cannot be
+// claimed by a create at the same time. It returns false if the device was claimed by someone else.
+func (d *Driver) deletePoolDevice(client *api.Client, device api.LocalVMDetails) (bool, error) {
+	resp, err := client.Devices.CompareAndSwapTags(device.LocalVMID, device.Tags, map[string]string{tagDriverVersion: Version})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusConflict {
+			log.Debugf("Pool device %v was claimed by someone else", device.LocalVMID)
+			return false, nil
+		}
+		return false, fmt.Errorf("could not claim pool device %v: %v", device.LocalVMID, err)
+	}
+	member := d.poolDeviceDriver(device.VMHostname)
+	member.LocalVMID = device.LocalVMID
+	if err := member.Remove(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// createPoolDevice creates or clones a device of the pool with a random hostname and password.
+func (d *Driver) createPoolDevice() (*Driver, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	member := d.poolDeviceDriver(poolDeviceHostname(d.Pool, hex.EncodeToString(suffix)))
+	password, err := generatePassword(generatedPasswordLength)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate device password: %v", err)
+	}
+	member.DevicePassword = password
+
+	log.Infof("Creating pool device %v...", member.MachineName)
+	tags := poolTags(d.Pool, poolStateProvisioning)
+	var deviceCreateResponse *api.DeviceCreateResponse
+	if member.cloneSourceVMID != "" {
+		deviceCreateResponse, err = member.cloneDevice(tags)
+	} else {
+		config := member.deviceCreateConfiguration()
+		config.Tags = tags
+		deviceCreateResponse, err = member.createDeviceWithConfiguration(config)
+	}
+	if err != nil {
+		return nil, err
+	}
+	member.LocalVMID = deviceCreateResponse.Device.LocalVMID
+	return member, nil
+}
+
+// poolDeviceDriver returns a driver for a device of the pool with the options of d.
+func (d *Driver) poolDeviceDriver(hostname string) *Driver {
+	return &Driver{
+		BaseDriver: &drivers.BaseDriver{
+			MachineName: hostname,
+			StorePath:   d.StorePath,
+		},
+		APIBaseURL:       d.APIBaseURL,
+		CPUCores:         d.CPUCores,
+		CredentialHelper: d.CredentialHelper,
+		DiskSize:         d.DiskSize,
+		KubernetesID:     d.KubernetesID,
+		Memory:           d.Memory,
+		NetworkID:        d.NetworkID,
+		Pool:             d.Pool,
+		Profile:          d.Profile,
+		SwapDiskSize:     d.SwapDiskSize,
+		TemplateID:       d.TemplateID,
+		TenantID:         d.TenantID,
+		Token:            d.Token,
+		cloneSourceVMID:  d.cloneSourceVMID,
+	}
+}
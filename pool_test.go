@@ -0,0 +1,270 @@
+package xelon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testPoolDevices = `[
+	{"localvmid":"pool2","created_at":"2026-10-19T11:00:00Z","vmhostname":"default-pool-2","tags":{"docker-machine-pool":"default","docker-machine-pool-state":"ready"}},
+	{"localvmid":"pool0","created_at":"2026-10-19T09:00:00Z","vmhostname":"default-pool-0","tags":{"docker-machine-pool":"default","docker-machine-pool-state":"provisioning"}},
+	{"localvmid":"%v","created_at":"2026-10-19T10:00:00Z","vmhostname":"default-pool-1","tags":{"docker-machine-pool":"default","docker-machine-pool-state":"ready"}}
+]`
+
+type tagsRequest struct {
+	Expected map[string]string `json:"expected"`
+	Tags     map[string]string `json:"tags"`
+}
+
+// setupPool serves the devices of testPoolDevices, with the device default-pool-0 still provisioning for
+// half an hour.
+func setupPool(t *testing.T, hostName, readyVMID string) (driver *Driver, mux *http.ServeMux, teardown func()) {
+	driver, mux, teardownServer := setup(hostName)
+	driver.Pool = defaultPool
+	mux.HandleFunc("/vmlist", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "default", r.URL.Query().Get("tags[docker-machine-pool]"))
+		_, _ = fmt.Fprintf(w, testPoolDevices, readyVMID)
+	})
+	now = func() time.Time { return time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC) }
+	teardown = func() {
+		now = time.Now
+		teardownServer()
+	}
+	return driver, mux, teardown
+}
+
+func TestDriver_Create_UsePool(t *testing.T) {
+	driver, mux, teardown := setupPool(t, "ci-runner-1", "localVMID")
+	defer teardown()
+	driver.UsePool = true
+	var claim tagsRequest
+	mux.HandleFunc("/vmlist/localVMID/tags", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		_ = json.NewDecoder(r.Body).Decode(&claim)
+	})
+	mux.HandleFunc("/vmlist/pool2/tags", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	})
+	renamed := ""
+	mux.HandleFunc("/vmlist/localVMID/rename", func(w http.ResponseWriter, r *http.Request) {
+		request := struct{ Hostname string }{}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		renamed = request.Hostname
+	})
+	mux.HandleFunc("/vmlist/create", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected create of a device")
+	})
+
+	err := driver.Create()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "localVMID", driver.LocalVMID)
+	assert.Equal(t, "ci-runner-1", renamed)
+	assert.Equal(t, "ready", claim.Expected[tagPoolState])
+	assert.Equal(t, "ci-runner-1", claim.Tags[tagMachineName])
+	assert.Equal(t, "true", claim.Tags[tagManaged])
+	assert.NotContains(t, claim.Tags, tagPool)
+	assert.Equal(t, "10.0.0.10", driver.IPAddress)
+	assert.Empty(t, driver.DevicePassword)
+}
+
+func TestDriver_Create_UsePool_empty(t *testing.T) {
+	driver, mux, teardown := setupPool(t, "ci-runner-1", "pool1")
+	defer teardown()
+	driver.UsePool = true
+	for _, pattern := range []string{"/vmlist/pool1/tags", "/vmlist/pool2/tags"} {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusConflict)
+		})
+	}
+	created := false
+	mux.HandleFunc("/vmlist/create", func(w http.ResponseWriter, r *http.Request) {
+		created = true
+		_, _ = fmt.Fprint(w, `{"device":{"localvmid":"localVMID"}}`)
+	})
+
+	err := driver.Create()
+
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, "localVMID", driver.LocalVMID)
+}
+
+func TestDriver_SyncPool_grow(t *testing.T) {
+	driver, mux, teardown := setupPool(t, "", "pool1")
+	defer teardown()
+	var mu sync.Mutex
+	var createTags []string
+	stopped := make(map[string]bool)
+	mux.HandleFunc("/vmlist/create", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		createTags = append(createTags, r.URL.Query().Get("tags[docker-machine-pool-state]"))
+		_, _ = fmt.Fprintf(w, `{"device":{"localvmid":"new%d"}}`, len(createTags))
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = fmt.Fprintf(w, testDeviceRoot, !stopped[r.URL.Query().Get("localvmid")])
+	})
+	var ready []tagsRequest
+	for _, id := range []string{"new1", "new2"} {
+		id := id
+		mux.HandleFunc("/vmlist/"+id+"/stopserver", func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			stopped[id] = true
+		})
+		mux.HandleFunc("/vmlist/"+id+"/tags", func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			assert.True(t, stopped[id])
+			var request tagsRequest
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			ready = append(ready, request)
+		})
+	}
+
+	result, err := driver.SyncPool(5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"new1", "new2"}, result.Created)
+	assert.Empty(t, result.Deleted)
+	assert.Equal(t, 5, result.Size)
+	assert.Equal(t, []string{"provisioning", "provisioning"}, createTags)
+	assert.Len(t, ready, 2)
+	assert.Equal(t, poolTags("default", poolStateProvisioning), ready[0].Expected)
+	assert.Equal(t, poolTags("default", poolStateReady), ready[0].Tags)
+}
+
+func TestDriver_SyncPool_shrink(t *testing.T) {
+	driver, mux, teardown := setupPool(t, "", "pool1")
+	defer teardown()
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"device":{"powerstate":false}}`)
+	})
+	var mu sync.Mutex
+	var deleted []string
+	for _, id := range []string{"pool1", "pool2"} {
+		id := id
+		mux.HandleFunc("/vmlist/"+id+"/tags", func(w http.ResponseWriter, r *http.Request) {
+			var request tagsRequest
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			assert.NotContains(t, request.Tags, tagPool)
+		})
+		mux.HandleFunc("/vmlist/"+id, func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, http.MethodDelete, r.Method)
+			deleted = append(deleted, id)
+		})
+	}
+
+	result, err := driver.SyncPool(2)
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.Created)
+	assert.Equal(t, []string{"pool1"}, result.Deleted)
+	assert.Equal(t, []string{"pool1"}, deleted)
+	assert.Equal(t, 2, result.Size)
+}
+
+func TestDriver_SyncPool_provisioningFails(t *testing.T) {
+	driver, mux, teardown := setupPool(t, "", "pool1")
+	defer teardown()
+	mux.HandleFunc("/vmlist/create", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"device":{"localvmid":"new1"}}`)
+	})
+	var mu sync.Mutex
+	stopped := false
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = fmt.Fprintf(w, testDeviceRoot, !stopped)
+	})
+	mux.HandleFunc("/vmlist/new1/stopserver", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		stopped = true
+	})
+	mux.HandleFunc("/vmlist/new1/tags", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	deleted := false
+	mux.HandleFunc("/vmlist/new1", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		deleted = true
+	})
+
+	result, err := driver.SyncPool(4)
+
+	assert.Error(t, err)
+	assert.True(t, deleted, "device which could not be provisioned must be deleted")
+	assert.Equal(t, []string{"new1"}, result.Created)
+	assert.Equal(t, []string{"new1"}, result.Deleted)
+	assert.Equal(t, 3, result.Size)
+}
+
+func TestDriver_SyncPool_staleProvisioning(t *testing.T) {
+	driver, mux, teardown := setupPool(t, "", "pool1")
+	defer teardown()
+	now = func() time.Time { return time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC) }
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"device":{"powerstate":false}}`)
+	})
+	var claim tagsRequest
+	mux.HandleFunc("/vmlist/pool0/tags", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&claim)
+	})
+	deleted := false
+	mux.HandleFunc("/vmlist/pool0", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		deleted = true
+	})
+
+	result, err := driver.SyncPool(2)
+
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	assert.Equal(t, poolStateProvisioning, claim.Expected[tagPoolState])
+	assert.Empty(t, result.Created)
+	assert.Equal(t, []string{"pool0"}, result.Deleted)
+	assert.Equal(t, 2, result.Size)
+}
+
+func TestDriver_SyncPool_dryRun(t *testing.T) {
+	driver, mux, teardown := setupPool(t, "", "pool1")
+	defer teardown()
+	driver.DryRun = true
+	mux.HandleFunc("/vmlist/create", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected create of a device")
+	})
+
+	_, err := driver.SyncPool(5)
+
+	assert.Error(t, err)
+}
+
+func TestDriver_validatePoolOptions(t *testing.T) {
+	tests := map[string]func(d *Driver){
+		"invalid pool name": func(d *Driver) { d.Pool = "-pool" },
+		"static ip address": func(d *Driver) { d.UsePool = true; d.StaticIPAddress = "10.0.0.20" },
+		"hypervisor system": func(d *Driver) { d.UsePool = true; d.HVSystemID = 3 },
+		"dry run":           func(d *Driver) { d.UsePool = true; d.DryRun = true },
+	}
+	for name, configure := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := NewDriver("ci-runner-1", "")
+			d.Pool = defaultPool
+			configure(d)
+
+			assert.Error(t, d.validatePoolOptions())
+		})
+	}
+}
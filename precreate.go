@@ -0,0 +1,131 @@
+package xelon
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon/api"
+)
+
+const maxHostnameLength = 253
+
+var hostnameLabelRegexp = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+
+// validateHostname checks that hostname is a valid host name according to RFC 1123.
+func validateHostname(hostname string) error {
+	if hostname == "" {
+		return fmt.Errorf("hostname cannot be empty")
+	}
+	if len(hostname) > maxHostnameLength {
+		return fmt.Errorf("hostname %q must be at most %d characters long", hostname, maxHostnameLength)
+	}
+	for _, label := range strings.Split(hostname, ".") {
+		if !hostnameLabelRegexp.MatchString(label) {
+			return fmt.Errorf("hostname %q is not valid according to RFC 1123: each label must be 1-63 characters long, "+
+				"contain only letters, digits and hyphens, and must not start or end with a hyphen", hostname)
+		}
+	}
+	return nil
+}
+
+// validateEndpoint checks that endpoint is an IP address or a valid host name without port or scheme.
+func validateEndpoint(endpoint string) error {
+	if net.ParseIP(endpoint) != nil {
+		return nil
+	}
+	if err := validateHostname(endpoint); err != nil {
+		return fmt.Errorf("xelon-public-endpoint %q must be an IP address or a host name without port or scheme", endpoint)
+	}
+	return nil
+}
+
+// validateEnginePortFamily checks that a non-default engine port is not used with an IPv6 address in the
+// Docker URL. docker-machine reads the engine port from the URL by splitting it at the first colon, so it
+// would configure the Docker engine on the default port for an IPv6 address.
+func (d *Driver) validateEnginePortFamily() error {
+	if d.EnginePort == defaultEnginePort {
+		return nil
+	}
+	ipv6 := d.IPFamily == ipFamilyIPv6 || d.IPFamily == ipFamilyPreferIPv6
+	if d.PublicEndpoint != "" {
+		ip := net.ParseIP(d.PublicEndpoint)
+		ipv6 = ip != nil && ip.To4() == nil
+	}
+	if ipv6 {
+		return fmt.Errorf("xelon-engine-port %d cannot be used with an IPv6 address, docker-machine only supports "+
+			"the default port %d for IPv6", d.EnginePort, defaultEnginePort)
+	}
+	return nil
+}
+
+// checkResourceLimits validates the requested device resources against the allowed ranges and the
+// remaining quotas of the tenant. All violations are reported at once.
+func (d *Driver) checkResourceLimits(client *api.Client) error {
+	limits, _, err := client.Tenant.GetLimits(d.TenantID)
+	if err != nil {
+		return err
+	}
+
+	resources := []struct {
+		flag  string
+		value int
+		limit api.ResourceLimit
+	}{
+		{"xelon-cpu-cores", d.CPUCores, limits.CPUCores},
+		{"xelon-memory", d.Memory, limits.Memory},
+		{"xelon-disk-size", d.DiskSize, limits.DiskSize},
+		{"xelon-swap-disk-size", d.SwapDiskSize, limits.SwapDiskSize},
+	}
+
+	var violations []string
+	for _, r := range resources {
+		if r.value <= 0 {
+			violations = append(violations, fmt.Sprintf("%v must be greater than 0, got %d", r.flag, r.value))
+			continue
+		}
+		if r.limit.Min > 0 && r.value < r.limit.Min || r.limit.Max > 0 && r.value > r.limit.Max {
+			violations = append(violations, fmt.Sprintf("%v must be %v, got %d", r.flag, describeLimit(r.limit), r.value))
+		}
+		if r.limit.Quota > 0 && r.limit.Used+r.value > r.limit.Quota {
+			violations = append(violations, fmt.Sprintf("%v of %d exceeds the remaining tenant quota of %d (%d of %d used)",
+				r.flag, r.value, r.limit.Quota-r.limit.Used, r.limit.Used, r.limit.Quota))
+		}
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("invalid device configuration:\n  - %v", strings.Join(violations, "\n  - "))
+	}
+
+	return nil
+}
+
+// describeLimit returns the allowed range of a resource limit, which may have only a minimum or a maximum.
+func describeLimit(limit api.ResourceLimit) string {
+	switch {
+	case limit.Max <= 0:
+		return fmt.Sprintf("at least %d", limit.Min)
+	case limit.Min <= 0:
+		return fmt.Sprintf("at most %d", limit.Max)
+	default:
+		return fmt.Sprintf("between %d and %d", limit.Min, limit.Max)
+	}
+}
+
+// checkHostnameCollision fails if a device with the same hostname already exists in the tenant.
+func (d *Driver) checkHostnameCollision(client *api.Client) error {
+	devices, _, err := client.Devices.List(d.TenantID, &api.DeviceListOptions{Hostname: d.MachineName})
+	if err != nil {
+		return err
+	}
+
+	for _, device := range devices {
+		if strings.EqualFold(device.VMHostname, d.MachineName) {
+			return fmt.Errorf("Xelon device with hostname %q already exists (localvmid: %v), "+
+				"use --xelon-allow-duplicate-name to create it anyway", d.MachineName, device.LocalVMID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package xelon
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateHostname(t *testing.T) {
+	tests := map[string]bool{
+		"default":                       true,
+		"ci-runner-1":                   true,
+		"ci-runner-1.example.com":       true,
+		"1runner":                       true,
+		"":                              false,
+		"-runner":                       false,
+		"runner-":                       false,
+		"ci_runner":                     false,
+		"ci..runner":                    false,
+		strings.Repeat("a", 64):         false,
+		strings.Repeat("a.", 127) + "a": false,
+	}
+
+	for hostname, valid := range tests {
+		err := validateHostname(hostname)
+		if valid {
+			assert.NoError(t, err, hostname)
+		} else {
+			assert.Error(t, err, hostname)
+		}
+	}
+}
+
+func TestValidateEndpoint(t *testing.T) {
+	assert.NoError(t, validateEndpoint("docker.example.com"))
+	assert.NoError(t, validateEndpoint("203.0.113.10"))
+	assert.NoError(t, validateEndpoint("2001:db8::10"))
+	assert.Error(t, validateEndpoint("docker.example.com:2376"))
+	assert.Error(t, validateEndpoint("https://docker.example.com"))
+}
+
+func TestDriver_PreCreateCheck_InvalidHostname(t *testing.T) {
+	driver, _, teardown := setup("ci_runner")
+	defer teardown()
+
+	err := driver.PreCreateCheck()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "RFC 1123")
+}
+
+func TestDriver_PreCreateCheck_HostnameCollision(t *testing.T) {
+	driver, mux, teardown := setup("ci-runner-1")
+	defer teardown()
+	mux.HandleFunc("/vmlist", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "ci-runner-1", r.URL.Query().Get("hostname"))
+		_, _ = fmt.Fprint(w, `[{"localvmid":"abc123","vmhostname":"ci-runner-1"}]`)
+	})
+
+	err := driver.PreCreateCheck()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "abc123")
+}
+
+func TestDriver_PreCreateCheck_HostnameCollisionAllowed(t *testing.T) {
+	driver, mux, teardown := setup("ci-runner-1")
+	defer teardown()
+	mux.HandleFunc("/vmlist", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[{"localvmid":"abc123","vmhostname":"ci-runner-1"}]`)
+	})
+	driver.AllowDuplicateName = true
+
+	err := driver.PreCreateCheck()
+
+	assert.NoError(t, err)
+}
+
+func TestDriver_PreCreateCheck_NoHostnameCollision(t *testing.T) {
+	driver, mux, teardown := setup("ci-runner-2")
+	defer teardown()
+	mux.HandleFunc("/vmlist", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[{"localvmid":"abc123","vmhostname":"ci-runner-10"}]`)
+	})
+
+	err := driver.PreCreateCheck()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "tenantID", driver.TenantID)
+}
+
+func TestDriver_PreCreateCheck_InvalidToken(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	mux.HandleFunc("/tenant", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"Unauthenticated user","code":401}`, http.StatusUnauthorized)
+	})
+
+	err := driver.PreCreateCheck()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "xelon-token is not valid")
+}
+
+func TestDriver_PreCreateCheck_ResourceLimits(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	mux.HandleFunc("/tenant/tenantID/limits", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"cpu":{"min":1,"max":8},"ram":{"min":1,"max":64,"quota":100,"used":96},"disk":{"min":10,"max":500}}`)
+	})
+	driver.CPUCores = 16
+	driver.Memory = 8
+	driver.DiskSize = 5
+	driver.SwapDiskSize = 0
+
+	err := driver.PreCreateCheck()
+
+	assert.Error(t, err)
+	assert.Equal(t, `invalid device configuration:
+  - xelon-cpu-cores must be between 1 and 8, got 16
+  - xelon-memory of 8 exceeds the remaining tenant quota of 4 (96 of 100 used)
+  - xelon-disk-size must be between 10 and 500, got 5
+  - xelon-swap-disk-size must be greater than 0, got 0`, err.Error())
+}
+
+func TestDriver_PreCreateCheck_OneSidedResourceLimits(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	mux.HandleFunc("/tenant/tenantID/limits", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"cpu":{"max":8},"disk":{"min":10}}`)
+	})
+	driver.CPUCores = 16
+	driver.DiskSize = 5
+
+	err := driver.PreCreateCheck()
+
+	assert.Error(t, err)
+	assert.Equal(t, `invalid device configuration:
+  - xelon-cpu-cores must be at most 8, got 16
+  - xelon-disk-size must be at least 10, got 5`, err.Error())
+}
@@ -0,0 +1,74 @@
+package xelon
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/docker/machine/libmachine/log"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon/api"
+)
+
+// deleteProtectionOverrideEnv is the environment variable which allows to remove a protected machine.
+const deleteProtectionOverrideEnv = "XELON_DELETE_PROTECTION_OVERRIDE"
+
+// SetDeleteProtection enables or disables the delete protection of the machine and mirrors it to the tags
+// of the device. Other tags of the device are preserved.
+func (d *Driver) SetDeleteProtection(enabled bool) error {
+	client, err := d.getClient()
+	if err != nil {
+		return err
+	}
+	deviceRoot, _, err := client.Devices.Get(d.TenantID, d.LocalVMID)
+	if err != nil {
+		return err
+	}
+
+	tags := make(map[string]string, len(deviceRoot.Device.Tags)+1)
+	for key, value := range deviceRoot.Device.Tags {
+		tags[key] = value
+	}
+	if enabled {
+		tags[tagDeleteProtection] = "true"
+	} else {
+		delete(tags, tagDeleteProtection)
+	}
+	if _, err := client.Devices.UpdateTags(d.LocalVMID, tags); err != nil {
+		return fmt.Errorf("could not update tags of device %v: %v", d.LocalVMID, err)
+	}
+	d.DeleteProtection = enabled
+	return nil
+}
+
+// checkDeleteProtection returns an error if the machine or the tags of its device are protected against
+// deletion, unless the protection is overridden with XELON_DELETE_PROTECTION_OVERRIDE. The tags are checked
+// as well, so a device is protected even if it is removed through another store or the companion command.
+func (d *Driver) checkDeleteProtection(client *api.Client) error {
+	if override, _ := strconv.ParseBool(os.Getenv(deleteProtectionOverrideEnv)); override {
+		if d.DeleteProtection {
+			log.Warnf("Delete protection of machine %v is overridden with %v", d.MachineName, deleteProtectionOverrideEnv)
+		}
+		return nil
+	}
+
+	protected := d.DeleteProtection
+	if !protected && d.LocalVMID != "" {
+		deviceRoot, resp, err := client.Devices.Get(d.TenantID, d.LocalVMID)
+		if err != nil {
+			// the device may no longer exist, which is handled when it is deleted; otherwise the protection
+			// is unknown and the machine is not removed
+			if resp == nil || resp.StatusCode != http.StatusNotFound {
+				return fmt.Errorf("could not check delete protection of machine %v: %v", d.MachineName, err)
+			}
+		} else {
+			protected = deviceRoot.Device.Tags[tagDeleteProtection] == "true"
+		}
+	}
+	if protected {
+		return fmt.Errorf("machine %v is protected against deletion, disable the protection with "+
+			"\"xelon-machine protection %v off\" or set %v=true", d.MachineName, d.MachineName, deleteProtectionOverrideEnv)
+	}
+	return nil
+}
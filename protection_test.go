@@ -0,0 +1,125 @@
+package xelon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupProtection(t *testing.T, tags string) (driver *Driver, mux *http.ServeMux, deleted *bool, teardown func()) {
+	driver, mux, teardown = setup("production-1")
+	driver.LocalVMID = "localVMID"
+	driver.TenantID = "tenantID"
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"device":{"powerstate":false,"tags":%v}}`, tags)
+	})
+	deleted = new(bool)
+	mux.HandleFunc("/vmlist/localVMID", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		*deleted = true
+	})
+	return driver, mux, deleted, teardown
+}
+
+func TestDriver_Remove_DeleteProtection(t *testing.T) {
+	driver, _, deleted, teardown := setupProtection(t, `{}`)
+	defer teardown()
+	driver.DeleteProtection = true
+
+	err := driver.Remove()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "xelon-machine protection production-1 off")
+	assert.False(t, *deleted)
+}
+
+func TestDriver_Remove_DeleteProtectionTag(t *testing.T) {
+	driver, _, deleted, teardown := setupProtection(t, `{"docker-machine-delete-protection":"true"}`)
+	defer teardown()
+
+	err := driver.Remove()
+
+	assert.Error(t, err)
+	assert.False(t, *deleted)
+}
+
+func TestDriver_Remove_DeleteProtectionUnknown(t *testing.T) {
+	driver, mux, teardown := setup("production-1")
+	defer teardown()
+	driver.LocalVMID = "localVMID"
+	driver.TenantID = "tenantID"
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/vmlist/localVMID", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("device must not be deleted")
+	})
+
+	err := driver.Remove()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "could not check delete protection")
+}
+
+func TestDriver_Remove_DeviceAlreadyDeleted(t *testing.T) {
+	driver, mux, teardown := setup("production-1")
+	defer teardown()
+	driver.LocalVMID = "localVMID"
+	driver.NetworkID = 7
+	driver.ReservedIPAddress = "10.0.0.20"
+	driver.TenantID = "tenantID"
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/vmlist/localVMID", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	released := false
+	mux.HandleFunc("/networks/7/ips/release", func(w http.ResponseWriter, r *http.Request) {
+		released = true
+	})
+
+	err := driver.Remove()
+
+	assert.NoError(t, err)
+	assert.True(t, released)
+}
+
+func TestDriver_Remove_DeleteProtectionOverride(t *testing.T) {
+	driver, _, deleted, teardown := setupProtection(t, `{"docker-machine-delete-protection":"true"}`)
+	defer teardown()
+	driver.DeleteProtection = true
+	defer setEnv(map[string]string{"XELON_DELETE_PROTECTION_OVERRIDE": "true"})()
+
+	err := driver.Remove()
+
+	assert.NoError(t, err)
+	assert.True(t, *deleted)
+}
+
+func TestDriver_SetDeleteProtection(t *testing.T) {
+	driver, mux, _, teardown := setupProtection(t, `{"docker-machine":"true","team":"platform"}`)
+	defer teardown()
+	var tags []map[string]string
+	mux.HandleFunc("/vmlist/localVMID/tags", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		request := struct {
+			Tags map[string]string `json:"tags"`
+		}{}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		tags = append(tags, request.Tags)
+	})
+
+	assert.NoError(t, driver.SetDeleteProtection(true))
+	assert.True(t, driver.DeleteProtection)
+	assert.NoError(t, driver.SetDeleteProtection(false))
+	assert.False(t, driver.DeleteProtection)
+
+	assert.Equal(t, []map[string]string{
+		{"docker-machine": "true", "docker-machine-delete-protection": "true", "team": "platform"},
+		{"docker-machine": "true", "team": "platform"},
+	}, tags)
+}
@@ -0,0 +1,162 @@
+package xelon
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/docker/machine/libmachine/log"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon/api"
+)
+
+// Actions which can be taken for expired devices.
+const (
+	ReapActionDelete = "delete"
+	ReapActionStop   = "stop"
+)
+
+// ExpiredDevice represents a device created for the store whose time to live has passed.
+type ExpiredDevice struct {
+	ExpiresAt    string `json:"expires_at"`
+	Hostname     string `json:"hostname"`
+	InStore      bool   `json:"in_store"`
+	LastActivity string `json:"last_activity,omitempty"`
+	LocalVMID    string `json:"localvmid"`
+	MachineName  string `json:"machine_name"`
+	Protected    bool   `json:"protected"`
+	// Skipped is the reason why the device is not reaped, if any.
+	Skipped string `json:"skipped,omitempty"`
+}
+
+// FindExpired lists the devices which were created with a time to live for the store at storePath and
+// have expired. Devices whose machine in the store shows activity within gracePeriod, or cannot be read,
+// are marked as skipped.
+func (a *Account) FindExpired(storePath string, gracePeriod time.Duration) ([]ExpiredDevice, error) {
+	client, err := a.client()
+	if err != nil {
+		return nil, err
+	}
+	machines, unreadable, err := loadMachines(storePath)
+	if err != nil {
+		return nil, err
+	}
+	unreadableMachines := make(map[string]bool, len(unreadable))
+	for _, name := range unreadable {
+		unreadableMachines[name] = true
+	}
+	known := make(map[string]*Driver, len(machines))
+	for _, machine := range machines {
+		if machine.LocalVMID != "" {
+			known[machine.LocalVMID] = machine
+		}
+	}
+
+	storeHash := storePathHash(storePath)
+	devices, _, err := client.Devices.List(a.TenantID, &api.DeviceListOptions{
+		Tags: map[string]string{tagManaged: "true", tagStorePathHash: storeHash},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list devices: %v", err)
+	}
+
+	current := now()
+	expired := []ExpiredDevice{}
+	for _, device := range devices {
+		if device.Tags[tagManaged] != "true" || device.Tags[tagStorePathHash] != storeHash || device.Tags[tagExpiresAt] == "" {
+			continue
+		}
+		expiresAt, err := time.Parse(time.RFC3339, device.Tags[tagExpiresAt])
+		if err != nil {
+			log.Warnf("Device %v has an invalid expiry time %q, skipping it", device.LocalVMID, device.Tags[tagExpiresAt])
+			continue
+		}
+		if expiresAt.After(current) {
+			continue
+		}
+
+		e := ExpiredDevice{
+			ExpiresAt:   expiresAt.UTC().Format(time.RFC3339),
+			Hostname:    device.VMHostname,
+			LocalVMID:   device.LocalVMID,
+			MachineName: device.Tags[tagMachineName],
+			Protected:   device.Tags[tagDeleteProtection] == "true",
+		}
+		if machine, ok := known[device.LocalVMID]; ok {
+			e.InStore = true
+			e.MachineName = machine.MachineName
+			e.Protected = e.Protected || machine.DeleteProtection
+			lastActivity, err := machineActivity(storePath, machine.MachineName)
+			if err != nil {
+				return nil, err
+			}
+			e.LastActivity = lastActivity.UTC().Format(time.RFC3339)
+			if current.Sub(lastActivity) < gracePeriod {
+				e.Skipped = fmt.Sprintf("machine was active within the grace period of %v", gracePeriod)
+			}
+		} else if unreadableMachines[firstString(e.MachineName, device.VMHostname)] {
+			// the machine may be in the store, so neither its activity nor its protection is known
+			e.Skipped = "machine in the store cannot be read"
+		}
+		expired = append(expired, e)
+	}
+
+	sort.Slice(expired, func(i, j int) bool { return expired[i].MachineName < expired[j].MachineName })
+	return expired, nil
+}
+
+// machineActivity returns the last modification time of the files of the machine in the store, which are
+// written by docker-machine and the driver whenever the machine is created, started, stopped or changed.
+func machineActivity(storePath, machineName string) (time.Time, error) {
+	entries, err := ioutil.ReadDir(filepath.Join(storePath, "machines", machineName))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not read machine %v: %v", machineName, err)
+	}
+	var lastActivity time.Time
+	for _, entry := range entries {
+		if entry.ModTime().After(lastActivity) {
+			lastActivity = entry.ModTime()
+		}
+	}
+	return lastActivity, nil
+}
+
+// ReapDevice stops or deletes an expired device. The machine of a deleted device is removed from the store
+// as well, so it doesn't refer to a device which no longer exists.
+func (a *Account) ReapDevice(storePath string, device ExpiredDevice, action string) error {
+	if action != ReapActionDelete && action != ReapActionStop {
+		return fmt.Errorf("invalid action %q, expected %v or %v", action, ReapActionStop, ReapActionDelete)
+	}
+
+	var d *Driver
+	if device.InStore {
+		machine, err := LoadDriver(storePath, device.MachineName)
+		if err != nil {
+			return err
+		}
+		d = machine
+	} else {
+		client, err := a.client()
+		if err != nil {
+			return err
+		}
+		d, err = a.deviceDriver(client, device.MachineName, device.LocalVMID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if action == ReapActionStop {
+		return d.Stop()
+	}
+	if err := d.Remove(); err != nil {
+		return err
+	}
+	if device.InStore {
+		return os.RemoveAll(filepath.Join(storePath, "machines", device.MachineName))
+	}
+	return nil
+}
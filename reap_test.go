@@ -0,0 +1,167 @@
+package xelon
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupExpired(t *testing.T) (account *Account, storePath string, mux *http.ServeMux, teardown func()) {
+	driver, mux, teardown := setup("ci-runner-1")
+	writeMachineConfig(t, driver.StorePath, "ci-runner-1", fmt.Sprintf(`{
+		"Driver": {"APIBaseURL": %q, "LocalVMID": "active", "MachineName": "ci-runner-1", "TenantID": "tenantID", "Token": "token"},
+		"DriverName": "xelon"
+	}`, driver.APIBaseURL))
+	writeMachineConfig(t, driver.StorePath, "ci-runner-2", fmt.Sprintf(`{
+		"Driver": {"APIBaseURL": %q, "LocalVMID": "idle", "MachineName": "ci-runner-2", "TenantID": "tenantID", "Token": "token"},
+		"DriverName": "xelon"
+	}`, driver.APIBaseURL))
+	idle := time.Now().Add(-3 * time.Hour)
+	if err := os.Chtimes(filepath.Join(driver.StorePath, "machines", "ci-runner-2", "config.json"), idle, idle); err != nil {
+		t.Fatal(err)
+	}
+	storeHash := storePathHash(driver.StorePath)
+	mux.HandleFunc("/vmlist", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, storeHash, r.URL.Query().Get("tags[docker-machine-store]"))
+		_, _ = fmt.Fprintf(w, `[
+			{"localvmid":"active","vmhostname":"ci-runner-1",
+			 "tags":{"docker-machine":"true","docker-machine-store":%[1]q,"docker-machine-expires-at":"2026-01-01T00:00:00Z"}},
+			{"localvmid":"idle","vmhostname":"ci-runner-2",
+			 "tags":{"docker-machine":"true","docker-machine-store":%[1]q,"docker-machine-expires-at":"2026-01-01T00:00:00Z"}},
+			{"localvmid":"failed","vmhostname":"ci-runner-3",
+			 "tags":{"docker-machine":"true","docker-machine-name":"ci-runner-3","docker-machine-store":%[1]q,"docker-machine-expires-at":"2026-01-01T06:00:00+02:00"}},
+			{"localvmid":"fresh","vmhostname":"ci-runner-4",
+			 "tags":{"docker-machine":"true","docker-machine-store":%[1]q,"docker-machine-expires-at":"2999-01-01T00:00:00Z"}},
+			{"localvmid":"permanent","vmhostname":"ci-runner-5","tags":{"docker-machine":"true","docker-machine-store":%[1]q}},
+			{"localvmid":"other-store","vmhostname":"ci-runner-6",
+			 "tags":{"docker-machine":"true","docker-machine-store":"other","docker-machine-expires-at":"2026-01-01T00:00:00Z"}}
+		]`, storeHash)
+	})
+
+	account = &Account{APIBaseURL: driver.APIBaseURL, TenantID: "tenantID", Token: "token"}
+	return account, driver.StorePath, mux, teardown
+}
+
+func TestAccount_FindExpired(t *testing.T) {
+	account, storePath, _, teardown := setupExpired(t)
+	defer teardown()
+
+	expired, err := account.FindExpired(storePath, time.Hour)
+
+	assert.NoError(t, err)
+	assert.Len(t, expired, 3)
+	assert.Equal(t, "active", expired[0].LocalVMID)
+	assert.True(t, expired[0].InStore)
+	assert.Contains(t, expired[0].Skipped, "grace period")
+	assert.Equal(t, "idle", expired[1].LocalVMID)
+	assert.True(t, expired[1].InStore)
+	assert.Empty(t, expired[1].Skipped)
+	assert.Equal(t, ExpiredDevice{
+		ExpiresAt:   "2026-01-01T04:00:00Z",
+		Hostname:    "ci-runner-3",
+		LocalVMID:   "failed",
+		MachineName: "ci-runner-3",
+	}, expired[2])
+}
+
+func TestAccount_FindExpired_unreadableMachine(t *testing.T) {
+	account, storePath, _, teardown := setupExpired(t)
+	defer teardown()
+	writeMachineConfig(t, storePath, "ci-runner-2", `{"Driver": {"LocalVMID": "idle"`)
+
+	expired, err := account.FindExpired(storePath, time.Hour)
+
+	assert.NoError(t, err)
+	assert.Len(t, expired, 3)
+	for _, e := range expired {
+		if e.LocalVMID == "idle" {
+			assert.False(t, e.InStore)
+			assert.Equal(t, "machine in the store cannot be read", e.Skipped)
+		}
+	}
+}
+
+func TestAccount_ReapDevice_delete(t *testing.T) {
+	account, storePath, mux, teardown := setupExpired(t)
+	defer teardown()
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"device":{"powerstate":false}}`)
+	})
+	deleted := false
+	mux.HandleFunc("/vmlist/idle", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		deleted = true
+	})
+
+	err := account.ReapDevice(storePath, ExpiredDevice{InStore: true, LocalVMID: "idle", MachineName: "ci-runner-2"}, ReapActionDelete)
+
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	_, err = os.Stat(filepath.Join(storePath, "machines", "ci-runner-2"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestAccount_ReapDevice_stop(t *testing.T) {
+	account, storePath, mux, teardown := setupExpired(t)
+	defer teardown()
+	stopped := false
+	mux.HandleFunc("/vmlist/failed/stopserver", func(w http.ResponseWriter, r *http.Request) {
+		stopped = true
+		_, _ = fmt.Fprint(w, `{}`)
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"device":{"powerstate":%v}}`, !stopped)
+	})
+
+	err := account.ReapDevice(storePath, ExpiredDevice{LocalVMID: "failed", MachineName: "ci-runner-3"}, ReapActionStop)
+
+	assert.NoError(t, err)
+	assert.True(t, stopped)
+}
+
+func TestDriver_SetConfigFromFlags_TTL(t *testing.T) {
+	teardown := useConfig(t, "")
+	defer teardown()
+
+	for ttl, valid := range map[string]bool{"6h": true, "90m": true, "6": false, "-1h": false} {
+		driver := NewDriver("default", "path")
+		flags := &drivers.CheckDriverOptions{
+			FlagsValues: map[string]interface{}{
+				"xelon-token": "token",
+				"xelon-ttl":   ttl,
+			},
+			CreateFlags: driver.GetCreateFlags(),
+		}
+
+		err := driver.SetConfigFromFlags(flags)
+
+		if valid {
+			assert.NoError(t, err, ttl)
+		} else {
+			assert.Error(t, err, ttl)
+		}
+	}
+}
+
+func TestDriver_Create_TTL(t *testing.T) {
+	driver, mux, teardown := setup("ci-runner-1")
+	defer teardown()
+	now = func() time.Time { return time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+	driver.TTL = 6 * time.Hour
+	mux.HandleFunc("/vmlist/create", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "2026-10-19T18:00:00Z", r.URL.Query().Get("tags[docker-machine-expires-at]"))
+		_, _ = fmt.Fprint(w, `{"device":{"localvmid":"localVMID"}}`)
+	})
+
+	err := driver.Create()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2026-10-19T18:00:00Z", driver.ExpiresAt)
+}
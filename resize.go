@@ -0,0 +1,76 @@
+package xelon
+
+import (
+	"fmt"
+
+	"github.com/docker/machine/libmachine/log"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon/api"
+)
+
+// Resize changes CPU cores and memory of the device and grows its disk, zero values are left unchanged.
+// A running device is stopped if CPU cores or memory change and started again afterwards.
+func (d *Driver) Resize(cpuCores, memory, diskSize int) error {
+	if cpuCores < 0 || memory < 0 || diskSize < 0 {
+		return fmt.Errorf("cpu cores, memory and disk size must not be negative")
+	}
+	if diskSize != 0 && diskSize < d.DiskSize {
+		return fmt.Errorf("disk size cannot be decreased from %d GB to %d GB", d.DiskSize, diskSize)
+	}
+
+	client, err := d.getClient()
+	if err != nil {
+		return err
+	}
+	deviceRoot, _, err := client.Devices.Get(d.TenantID, d.LocalVMID)
+	if err != nil {
+		return err
+	}
+	device := deviceRoot.Device
+
+	restart := device.Powerstate &&
+		(cpuCores != 0 && cpuCores != device.CPU || memory != 0 && memory != device.RAM)
+	if restart {
+		log.Info("Stopping Xelon device...")
+		if err := d.stopDevice(); err != nil {
+			return err
+		}
+	}
+
+	log.Info("Reconfiguring Xelon device...")
+	request := &api.DeviceReconfigureRequest{
+		CPUCores: cpuCores,
+		DiskSize: diskSize,
+		Memory:   memory,
+	}
+	if _, err := client.Devices.Reconfigure(d.TenantID, d.LocalVMID, request); err != nil {
+		if restart {
+			// the device was running before, so it is not left stopped by a failed reconfiguration
+			log.Info("Starting Xelon device again...")
+			if startErr := d.startDevice(); startErr != nil {
+				log.Warnf("Could not start Xelon device again: %v", startErr)
+			} else if startErr := d.waitForDeviceRunning(); startErr != nil {
+				log.Warnf("Xelon device did not start again: %v", startErr)
+			}
+		}
+		return err
+	}
+	if cpuCores != 0 {
+		d.CPUCores = cpuCores
+	}
+	if diskSize != 0 {
+		d.DiskSize = diskSize
+	}
+	if memory != 0 {
+		d.Memory = memory
+	}
+
+	if restart {
+		log.Info("Starting Xelon device...")
+		if err := d.startDevice(); err != nil {
+			return err
+		}
+		return d.waitForDeviceRunning()
+	}
+	return nil
+}
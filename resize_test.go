@@ -0,0 +1,69 @@
+package xelon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon/api"
+)
+
+func TestDriver_Resize(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.LocalVMID = "localVMID"
+	driver.TenantID = "tenantID"
+	var calls []string
+	mux.HandleFunc("/vmlist/localVMID/reconfigure", func(w http.ResponseWriter, r *http.Request) {
+		request := new(api.DeviceReconfigureRequest)
+		_ = json.NewDecoder(r.Body).Decode(request)
+		calls = append(calls, fmt.Sprintf("reconfigure %+v", *request))
+		_, _ = fmt.Fprint(w, `{}`)
+	})
+	// the device of the fake API is running, so Reconfigure fails unless the device is stopped before
+	err := driver.Resize(4, 8, 50)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"reconfigure {CPUCores:4 DiskSize:50 Memory:8}"}, calls)
+	assert.Equal(t, 4, driver.CPUCores)
+	assert.Equal(t, 8, driver.Memory)
+	assert.Equal(t, 50, driver.DiskSize)
+	s, _ := driver.GetState()
+	assert.Equal(t, "Running", s.String())
+}
+
+func TestDriver_Resize_shrinkDisk(t *testing.T) {
+	driver, _, teardown := setup("default")
+	defer teardown()
+	driver.LocalVMID = "localVMID"
+	driver.TenantID = "tenantID"
+	driver.DiskSize = 50
+
+	err := driver.Resize(0, 0, 20)
+
+	assert.Error(t, err)
+	assert.Equal(t, 50, driver.DiskSize)
+}
+
+func TestDriver_Resize_reconfigureFails(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.LocalVMID = "localVMID"
+	driver.TenantID = "tenantID"
+	mux.HandleFunc("/vmlist/localVMID/reconfigure", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = fmt.Fprint(w, `{"error":"not enough resources"}`)
+	})
+
+	err := driver.Resize(4, 8, 0)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "422")
+	assert.Equal(t, defaultCPUCores, driver.CPUCores)
+	// the device was running before, so it is started again
+	s, _ := driver.GetState()
+	assert.Equal(t, "Running", s.String())
+}
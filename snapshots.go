@@ -0,0 +1,135 @@
+package xelon
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/log"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon/api"
+)
+
+const (
+	defaultSnapshotRetention = 3
+	stopSnapshotPrefix       = "docker-machine-stop-"
+)
+
+// snapshotOnStop takes a snapshot of the stopped device and deletes the oldest snapshots taken on stop,
+// so only the last SnapshotRetention snapshots are kept.
+func (d *Driver) snapshotOnStop() error {
+	client, err := d.getClient()
+	if err != nil {
+		return err
+	}
+
+	existing, _, err := client.Snapshots.List(d.LocalVMID)
+	if err != nil {
+		return err
+	}
+	snapshotCreateRequest := &api.SnapshotCreateRequest{
+		Name: stopSnapshotName(now(), existing),
+	}
+	log.Infof("Taking snapshot %v of Xelon device...", snapshotCreateRequest.Name)
+	if _, _, err := client.Snapshots.Create(d.LocalVMID, snapshotCreateRequest); err != nil {
+		return fmt.Errorf("could not take snapshot of Xelon device: %v", err)
+	}
+
+	snapshots, _, err := client.Snapshots.List(d.LocalVMID)
+	if err != nil {
+		return err
+	}
+	var stopSnapshots []api.Snapshot
+	for _, snapshot := range snapshots {
+		if strings.HasPrefix(snapshot.Name, stopSnapshotPrefix) {
+			stopSnapshots = append(stopSnapshots, snapshot)
+		}
+	}
+	// snapshot names contain the timestamp, so sorting by name sorts them from the oldest to the newest
+	sort.Slice(stopSnapshots, func(i, j int) bool { return stopSnapshots[i].Name < stopSnapshots[j].Name })
+	for len(stopSnapshots) > d.SnapshotRetention {
+		log.Debugf("Deleting snapshot %v of Xelon device...", stopSnapshots[0].Name)
+		if _, err := client.Snapshots.Delete(d.LocalVMID, stopSnapshots[0].ID); err != nil {
+			return fmt.Errorf("could not delete snapshot %v of Xelon device: %v", stopSnapshots[0].Name, err)
+		}
+		stopSnapshots = stopSnapshots[1:]
+	}
+
+	return nil
+}
+
+// stopSnapshotName returns the name of a snapshot taken on stop at t. If a snapshot with that name already
+// exists, e.g. after two stops within the same second, the timestamp is advanced until the name is unique,
+// so the names still sort from the oldest to the newest.
+func stopSnapshotName(t time.Time, existing []api.Snapshot) string {
+	names := make(map[string]bool, len(existing))
+	for _, snapshot := range existing {
+		names[snapshot.Name] = true
+	}
+	for {
+		name := stopSnapshotPrefix + t.UTC().Format("20060102T150405Z")
+		if !names[name] {
+			return name
+		}
+		t = t.Add(time.Second)
+	}
+}
+
+// ListSnapshots returns all snapshots of the device.
+func (d *Driver) ListSnapshots() ([]api.Snapshot, error) {
+	client, err := d.getClient()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, _, err := client.Snapshots.List(d.LocalVMID)
+	return snapshots, err
+}
+
+// RestoreSnapshot reverts the device to the snapshot with the given name. A running device is stopped
+// before and started again after the restore.
+func (d *Driver) RestoreSnapshot(name string) error {
+	snapshots, err := d.ListSnapshots()
+	if err != nil {
+		return err
+	}
+	var snapshot *api.Snapshot
+	for i := range snapshots {
+		if snapshots[i].Name == name {
+			snapshot = &snapshots[i]
+			break
+		}
+	}
+	if snapshot == nil {
+		return fmt.Errorf("snapshot %q of Xelon device %v not found", name, d.LocalVMID)
+	}
+
+	client, err := d.getClient()
+	if err != nil {
+		return err
+	}
+	deviceRoot, _, err := client.Devices.Get(d.TenantID, d.LocalVMID)
+	if err != nil {
+		return err
+	}
+	running := deviceRoot.Device.Powerstate
+
+	if running {
+		log.Info("Stopping Xelon device...")
+		if err := d.stopDevice(); err != nil {
+			return err
+		}
+	}
+
+	log.Infof("Restoring Xelon device to snapshot %v...", snapshot.Name)
+	if _, err := client.Snapshots.Restore(d.LocalVMID, snapshot.ID); err != nil {
+		return err
+	}
+
+	if running {
+		log.Info("Starting Xelon device...")
+		return d.startDevice()
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package xelon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Xelon-AG/docker-machine-driver-xelon/api"
+)
+
+func TestDriver_Stop_SnapshotOnStop(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.LocalVMID = "localVMID"
+	driver.TenantID = "tenantID"
+	driver.SnapshotOnStop = true
+	driver.SnapshotRetention = 2
+	now = func() time.Time { return time.Date(2020, 1, 4, 10, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+	var createdSnapshot string
+	var deletedSnapshots []string
+	mux.HandleFunc("/vmlist/localVMID/snapshots/create", func(w http.ResponseWriter, r *http.Request) {
+		request := new(api.SnapshotCreateRequest)
+		_ = json.NewDecoder(r.Body).Decode(request)
+		createdSnapshot = request.Name
+		_, _ = fmt.Fprint(w, `{"id":4}`)
+	})
+	mux.HandleFunc("/vmlist/localVMID/snapshots", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `[
+			{"id":3,"name":"docker-machine-stop-20200103T100000Z"},
+			{"id":1,"name":"docker-machine-stop-20200101T100000Z"},
+			{"id":5,"name":"before-upgrade"},
+			{"id":2,"name":"docker-machine-stop-20200102T100000Z"},
+			{"id":4,"name":"%v"}
+		]`, createdSnapshot)
+	})
+	mux.HandleFunc("/vmlist/localVMID/snapshots/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		deletedSnapshots = append(deletedSnapshots, strings.TrimPrefix(r.URL.Path, "/vmlist/localVMID/snapshots/"))
+		_, _ = fmt.Fprint(w, `{}`)
+	})
+
+	err := driver.Stop()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "docker-machine-stop-20200104T100000Z", createdSnapshot)
+	assert.Equal(t, []string{"1", "2"}, deletedSnapshots)
+}
+
+func TestStopSnapshotName_unique(t *testing.T) {
+	existing := []api.Snapshot{
+		{Name: "docker-machine-stop-20200104T100000Z"},
+		{Name: "docker-machine-stop-20200104T100001Z"},
+	}
+
+	name := stopSnapshotName(time.Date(2020, 1, 4, 10, 0, 0, 0, time.UTC), existing)
+
+	assert.Equal(t, "docker-machine-stop-20200104T100002Z", name)
+}
+
+func TestDriver_Stop_WithoutSnapshot(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.LocalVMID = "localVMID"
+	driver.TenantID = "tenantID"
+	mux.HandleFunc("/vmlist/localVMID/snapshots/create", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("snapshot must not be taken")
+	})
+
+	err := driver.Stop()
+
+	assert.NoError(t, err)
+}
+
+func TestDriver_RestoreSnapshot(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.LocalVMID = "localVMID"
+	driver.TenantID = "tenantID"
+	var calls []string
+	mux.HandleFunc("/vmlist/localVMID/snapshots", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[{"id":1,"name":"before-upgrade"},{"id":2,"name":"after-upgrade"}]`)
+	})
+	mux.HandleFunc("/vmlist/localVMID/snapshots/2/restore", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "restore")
+		_, _ = fmt.Fprint(w, `{}`)
+	})
+
+	err := driver.RestoreSnapshot("after-upgrade")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"restore"}, calls)
+	s, _ := driver.GetState()
+	assert.Equal(t, "Running", s.String())
+}
+
+func TestDriver_RestoreSnapshot_notFound(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.LocalVMID = "localVMID"
+	mux.HandleFunc("/vmlist/localVMID/snapshots", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[{"id":1,"name":"before-upgrade"}]`)
+	})
+
+	err := driver.RestoreSnapshot("after-upgrade")
+
+	assert.Error(t, err)
+}
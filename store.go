@@ -0,0 +1,165 @@
+package xelon
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/machine/libmachine/auth"
+	"github.com/docker/machine/libmachine/engine"
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/swarm"
+)
+
+// machineConfig represents the parts of a docker-machine host configuration (config.json)
+// which are used by the driver.
+type machineConfig struct {
+	Driver     json.RawMessage
+	DriverName string
+}
+
+// LoadDriver reads the driver of the machine from the docker-machine store at storePath.
+func LoadDriver(storePath, machineName string) (*Driver, error) {
+	path := filepath.Join(storePath, "machines", machineName, "config.json")
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read machine %v: %v", machineName, err)
+	}
+
+	config := new(machineConfig)
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("could not parse machine config %v: %v", path, err)
+	}
+	if config.DriverName != "xelon" {
+		return nil, &otherDriverError{MachineName: machineName, DriverName: config.DriverName}
+	}
+
+	d := NewDriver(machineName, storePath)
+	if err := json.Unmarshal(config.Driver, d); err != nil {
+		return nil, fmt.Errorf("could not parse driver config of machine %v: %v", machineName, err)
+	}
+	// the store may have been moved since the machine was created
+	d.StorePath = storePath
+
+	return d, nil
+}
+
+// otherDriverError is returned by LoadDriver for a machine which doesn't use the xelon driver.
+type otherDriverError struct {
+	MachineName string
+	DriverName  string
+}
+
+func (e *otherDriverError) Error() string {
+	return fmt.Sprintf("machine %v uses driver %q instead of xelon", e.MachineName, e.DriverName)
+}
+
+// loadMachines reads the drivers of all xelon machines in the docker-machine store at storePath. Machines
+// of other drivers are skipped. The names of machines which cannot be read are returned separately, since
+// they may be xelon machines whose devices must not be touched.
+func loadMachines(storePath string) (machines []*Driver, unreadable []string, err error) {
+	entries, err := ioutil.ReadDir(filepath.Join(storePath, "machines"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("could not list machines: %v", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		machine, err := LoadDriver(storePath, entry.Name())
+		if err != nil {
+			if _, ok := err.(*otherDriverError); !ok {
+				log.Warnf("Could not read machine %v, skipping its device: %v", entry.Name(), err)
+				unreadable = append(unreadable, entry.Name())
+			}
+			continue
+		}
+		machines = append(machines, machine)
+	}
+	return machines, unreadable, nil
+}
+
+// SaveDriver writes the driver to the configuration of its machine in the docker-machine store. All other
+// parts of the machine configuration are preserved.
+func SaveDriver(d *Driver) error {
+	path := d.ResolveStorePath("config.json")
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("could not read machine %v: %v", d.MachineName, err)
+	}
+
+	var config map[string]json.RawMessage
+	if err := json.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("could not parse machine config %v: %v", path, err)
+	}
+	config["Driver"], err = json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	data, err = json.MarshalIndent(config, "", "    ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// hostConfig represents a docker-machine host configuration (config.json) as written by docker-machine create.
+type hostConfig struct {
+	ConfigVersion int
+	Driver        *Driver
+	DriverName    string
+	HostOptions   *hostOptions
+	Name          string
+}
+
+// hostOptions represents the host options of a docker-machine host configuration.
+type hostOptions struct {
+	Driver        string
+	Memory        int
+	Disk          int
+	EngineOptions *engine.Options
+	SwarmOptions  *swarm.Options
+	AuthOptions   *auth.Options
+}
+
+// saveHost writes the configuration of a machine to the docker-machine store, which is created if necessary.
+func saveHost(storePath string, config *hostConfig) error {
+	dir := filepath.Join(storePath, "machines", config.Name)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("could not create machine %v: %v", config.Name, err)
+	}
+	data, err := json.MarshalIndent(config, "", "    ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(dir, "config.json"), data)
+}
+
+// writeFileAtomic writes data to a temporary file first and renames it to path, so the file is never
+// partially written.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,99 @@
+package xelon
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeMachineConfig(t *testing.T, storePath, machineName, content string) {
+	dir := filepath.Join(storePath, "machines", machineName)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "config.json"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadDriver(t *testing.T) {
+	storePath, _ := ioutil.TempDir("", "xelon")
+	defer os.RemoveAll(storePath)
+	writeMachineConfig(t, storePath, "ci-runner-1", `{
+		"ConfigVersion": 3,
+		"Driver": {"IPAddress": "10.0.0.10", "MachineName": "ci-runner-1", "LocalVMID": "localVMID", "TenantID": "tenantID"},
+		"DriverName": "xelon"
+	}`)
+
+	driver, err := LoadDriver(storePath, "ci-runner-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "localVMID", driver.LocalVMID)
+	assert.Equal(t, "tenantID", driver.TenantID)
+	assert.Equal(t, "10.0.0.10", driver.IPAddress)
+	assert.Equal(t, "ci-runner-1", driver.MachineName)
+}
+
+func TestLoadDriver_otherDriver(t *testing.T) {
+	storePath, _ := ioutil.TempDir("", "xelon")
+	defer os.RemoveAll(storePath)
+	writeMachineConfig(t, storePath, "local", `{"Driver": {}, "DriverName": "virtualbox"}`)
+
+	_, err := LoadDriver(storePath, "local")
+
+	assert.Error(t, err)
+}
+
+func TestLoadMachines(t *testing.T) {
+	storePath, _ := ioutil.TempDir("", "xelon")
+	defer os.RemoveAll(storePath)
+	writeMachineConfig(t, storePath, "ci-runner-1", `{"Driver": {"LocalVMID": "localVMID"}, "DriverName": "xelon"}`)
+	writeMachineConfig(t, storePath, "ci-runner-2", `{"Driver": `)
+	writeMachineConfig(t, storePath, "local", `{"Driver": {}, "DriverName": "virtualbox"}`)
+
+	machines, unreadable, err := loadMachines(storePath)
+
+	assert.NoError(t, err)
+	assert.Len(t, machines, 1)
+	assert.Equal(t, "localVMID", machines[0].LocalVMID)
+	assert.Equal(t, []string{"ci-runner-2"}, unreadable)
+}
+
+func TestLoadDriver_notFound(t *testing.T) {
+	storePath, _ := ioutil.TempDir("", "xelon")
+	defer os.RemoveAll(storePath)
+
+	_, err := LoadDriver(storePath, "missing")
+
+	assert.Error(t, err)
+}
+
+func TestSaveDriver(t *testing.T) {
+	storePath, _ := ioutil.TempDir("", "xelon")
+	defer os.RemoveAll(storePath)
+	writeMachineConfig(t, storePath, "ci-runner-1", `{
+		"ConfigVersion": 3,
+		"Driver": {"MachineName": "ci-runner-1", "LocalVMID": "localVMID", "CPUCores": 2},
+		"DriverName": "xelon",
+		"HostOptions": {"Driver": ""}
+	}`)
+	driver, _ := LoadDriver(storePath, "ci-runner-1")
+	driver.CPUCores = 4
+
+	err := SaveDriver(driver)
+
+	assert.NoError(t, err)
+	saved, _ := LoadDriver(storePath, "ci-runner-1")
+	assert.Equal(t, 4, saved.CPUCores)
+	assert.Equal(t, "localVMID", saved.LocalVMID)
+	data, _ := ioutil.ReadFile(filepath.Join(storePath, "machines", "ci-runner-1", "config.json"))
+	assert.Contains(t, string(data), `"HostOptions"`)
+	info, _ := os.Stat(filepath.Join(storePath, "machines", "ci-runner-1", "config.json"))
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+}
@@ -0,0 +1,107 @@
+package xelon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Version is the version of the driver. It is set at build time with
+// -ldflags "-X github.com/Xelon-AG/docker-machine-driver-xelon.Version=<version>".
+var Version = "dev"
+
+// Tags which are set on every device created by the driver.
+const (
+	tagManaged       = "docker-machine"
+	tagMachineName   = "docker-machine-name"
+	tagStorePathHash = "docker-machine-store"
+	tagDriverVersion = "docker-machine-driver-version"
+	// tagAntiAffinityGroup is only set on devices of an anti-affinity group.
+	tagAntiAffinityGroup = "docker-machine-anti-affinity-group"
+	// tagDeleteProtection and tagExpiresAt are only set on protected devices and devices with a time to live.
+	tagDeleteProtection = "docker-machine-delete-protection"
+	tagExpiresAt        = "docker-machine-expires-at"
+	// tagPool and tagPoolState are only set on unclaimed pool devices, which are not managed machines yet.
+	tagPool      = "docker-machine-pool"
+	tagPoolState = "docker-machine-pool-state"
+)
+
+var (
+	automaticTags = map[string]bool{
+		tagManaged:           true,
+		tagMachineName:       true,
+		tagStorePathHash:     true,
+		tagDriverVersion:     true,
+		tagAntiAffinityGroup: true,
+		tagDeleteProtection:  true,
+		tagExpiresAt:         true,
+		tagPool:              true,
+		tagPoolState:         true,
+	}
+	tagKeyRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/-]{0,62}$`)
+)
+
+const maxTagValueLength = 255
+
+// parseTags parses tags in the form "key=value". Keys must be unique and must not be one of the tags
+// which are set automatically.
+func parseTags(specs []string) (map[string]string, error) {
+	tags := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		kv := strings.SplitN(spec, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid tag %q, expected key=value", spec)
+		}
+		key, value := kv[0], kv[1]
+		if !tagKeyRegexp.MatchString(key) {
+			return nil, fmt.Errorf("invalid tag key %q, expected up to 63 letters, digits, '.', '_', '/' or '-'", key)
+		}
+		if len(value) > maxTagValueLength {
+			return nil, fmt.Errorf("value of tag %v is longer than %d characters", key, maxTagValueLength)
+		}
+		if automaticTags[key] {
+			return nil, fmt.Errorf("tag %v is set by the driver and cannot be overridden", key)
+		}
+		if _, ok := tags[key]; ok {
+			return nil, fmt.Errorf("tag %v is given more than once", key)
+		}
+		tags[key] = value
+	}
+	return tags, nil
+}
+
+// storePathHash returns a short hash of the docker-machine store path, which identifies the store
+// a device belongs to without disclosing the path.
+func storePathHash(storePath string) string {
+	if absPath, err := filepath.Abs(storePath); err == nil {
+		storePath = absPath
+	}
+	sum := sha256.Sum256([]byte(filepath.Clean(storePath)))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// deviceTags returns the user-supplied tags of the device together with the tags which identify it as
+// created by the driver.
+func (d *Driver) deviceTags() map[string]string {
+	tags := make(map[string]string, len(d.Tags)+len(automaticTags))
+	for key, value := range d.Tags {
+		tags[key] = value
+	}
+	tags[tagManaged] = "true"
+	tags[tagMachineName] = d.MachineName
+	tags[tagStorePathHash] = storePathHash(d.StorePath)
+	tags[tagDriverVersion] = Version
+	if d.AntiAffinityGroup != "" {
+		tags[tagAntiAffinityGroup] = d.AntiAffinityGroup
+	}
+	if d.DeleteProtection {
+		tags[tagDeleteProtection] = "true"
+	}
+	if d.ExpiresAt != "" {
+		tags[tagExpiresAt] = d.ExpiresAt
+	}
+	return tags
+}
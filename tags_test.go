@@ -0,0 +1,79 @@
+package xelon
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTags(t *testing.T) {
+	tags, err := parseTags([]string{"team=platform", "cost-center=4711", "note=a=b", "empty="})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "platform", "cost-center": "4711", "note": "a=b", "empty": ""}, tags)
+}
+
+func TestParseTags_invalid(t *testing.T) {
+	tests := map[string][]string{
+		"missing value": {"team"},
+		"empty key":     {"=platform"},
+		"invalid key":   {"team name=platform"},
+		"long value":    {"team=" + strings.Repeat("x", 256)},
+		"automatic tag": {"docker-machine-name=other"},
+		"duplicate key": {"team=platform", "team=infra"},
+	}
+	for name, specs := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseTags(specs)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestStorePathHash(t *testing.T) {
+	assert.Len(t, storePathHash("/home/ci/.docker/machine"), 12)
+	assert.Equal(t, storePathHash("/home/ci/.docker/machine"), storePathHash("/home/ci/.docker/machine/"))
+	assert.NotEqual(t, storePathHash("/home/ci/.docker/machine"), storePathHash("/home/dev/.docker/machine"))
+}
+
+func TestDriver_SetConfigFromFlags_Tags(t *testing.T) {
+	teardown := useConfig(t, "")
+	defer teardown()
+	driver := NewDriver("default", "path")
+	flags := &drivers.CheckDriverOptions{
+		FlagsValues: map[string]interface{}{
+			"xelon-tag":   []string{"team=platform"},
+			"xelon-token": "token",
+		},
+		CreateFlags: driver.GetCreateFlags(),
+	}
+
+	err := driver.SetConfigFromFlags(flags)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "platform"}, driver.Tags)
+}
+
+func TestDriver_Create_Tags(t *testing.T) {
+	driver, mux, teardown := setup("ci-runner-1")
+	defer teardown()
+	driver.Tags = map[string]string{"team": "platform"}
+	mux.HandleFunc("/vmlist/create", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "platform", query.Get("tags[team]"))
+		assert.Equal(t, "true", query.Get("tags[docker-machine]"))
+		assert.Equal(t, "ci-runner-1", query.Get("tags[docker-machine-name]"))
+		assert.Equal(t, storePathHash(driver.StorePath), query.Get("tags[docker-machine-store]"))
+		assert.Equal(t, Version, query.Get("tags[docker-machine-driver-version]"))
+		_, _ = fmt.Fprint(w, `{"device":{"localvmid":"localVMID"}}`)
+	})
+
+	err := driver.Create()
+
+	assert.NoError(t, err)
+}
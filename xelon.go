@@ -6,6 +6,8 @@ import (
 	"math/rand"
 	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/docker/machine/libmachine/drivers"
@@ -18,28 +20,81 @@ import (
 )
 
 const (
-	defaultCPUCores       = 2
-	defaultDevicePassword = "Xelon22"
-	defaultDiskSize       = 20
-	defaultKubernetesID   = "kub1"
-	defaultMemory         = 2
-	defaultSSHPort        = 22
-	defaultSSHUser        = "root"
-	defaultSwapDiskSize   = 2
+	defaultCPUCores     = 2
+	defaultDiskSize     = 20
+	defaultEnginePort   = 2376
+	defaultKubernetesID = "kub1"
+	defaultMemory       = 2
+	defaultSSHPort      = 22
+	defaultSSHUser      = "root"
+	defaultSwapDiskSize = 2
+
+	deviceStatePollInterval = 2 * time.Second
+	deviceStateTimeout      = 5 * time.Minute
+	guestToolsRunning       = "guestToolsRunning"
+	rebootStartTimeout      = 1 * time.Minute
+)
+
+var (
+	// sleep, now and runSSHCommand are replaced in tests
+	sleep         = time.Sleep
+	now           = time.Now
+	runSSHCommand = drivers.RunSSHCommandFromDriver
 )
 
 type Driver struct {
 	*drivers.BaseDriver
-	APIBaseURL     string
-	CPUCores       int
-	DevicePassword string
-	DiskSize       int
-	KubernetesID   string
-	LocalVMID      string
-	Memory         int
-	SwapDiskSize   int
-	TenantID       string
-	Token          string
+	APIBaseURL                        string
+	AllowDuplicateName                bool
+	AllowedSourceCIDR                 string
+	AntiAffinityGroup                 string
+	CloneFrom                         string
+	CPUCores                          int
+	CredentialHelper                  string
+	DataDisks                         []DataDisk
+	DeleteProtection                  bool
+	DevicePassword                    string
+	DevicePasswordMinCharacterClasses int
+	DevicePasswordMinLength           int
+	DiskSize                          int
+	DNSRecords                        []DNSRecord
+	DNSTTL                            int
+	DNSZone                           string
+	DryRun                            bool
+	EnginePort                        int
+	ExpiresAt                         string
+	FirewallRuleIDs                   []int
+	HVSystemID                        int
+	IPFamily                          string
+	IPVisibility                      string
+	KeepIP                            bool
+	KubernetesID                      string
+	LocalVMID                         string
+	Memory                            int
+	NetworkID                         int
+	NICLabel                          string
+	Plan                              string
+	Profile                           string
+	Pool                              string
+	PublicEndpoint                    string
+	ReservedIPAddress                 string
+	SnapshotOnStop                    bool
+	SnapshotRetention                 int
+	StaticIPAddress                   string
+	SwapDiskSize                      int
+	Tags                              map[string]string
+	TemplateID                        int
+	TenantID                          string
+	Token                             string
+	TTL                               time.Duration
+	UsePool                           bool
+
+	// cloneSourceVMID is the localvmid of the device which is cloned, if xelon-clone-from names a device.
+	cloneSourceVMID string
+	// placements is shared by the machines which are created together by BulkCreate.
+	placements *placements
+	// recorder collects the requests which change resources in a dry run.
+	recorder *api.RequestRecorder
 }
 
 func NewDriver(hostName, storePath string) *Driver {
@@ -52,39 +107,151 @@ func NewDriver(hostName, storePath string) *Driver {
 }
 
 func (d *Driver) Create() error {
+	if d.DryRun {
+		d.recorder = &api.RequestRecorder{}
+	}
+
 	log.Info("Authenticating into Xelon VDC...")
-	client := d.getClient()
-	tenant, _, err := client.Tenant.Get()
+	client, err := d.getClient()
 	if err != nil {
 		return err
 	}
-	log.Debugf("User tenant id: %v", tenant.TenantIdentifier)
-	d.TenantID = tenant.TenantIdentifier
+	if d.TenantID == "" {
+		tenant, _, err := client.Tenant.Get()
+		if err != nil {
+			return err
+		}
+		d.TenantID = tenant.TenantIdentifier
+	}
+	log.Debugf("User tenant id: %v", d.TenantID)
+
+	if d.CloneFrom != "" {
+		if err := d.resolveCloneSource(client); err != nil {
+			return err
+		}
+	}
 
 	log.Debug("(workaround): generate random delay before creating Xelon device...")
 	randomDelay()
 
+	if d.AntiAffinityGroup != "" {
+		log.Infof("Placing Xelon device apart from anti-affinity group %v...", d.AntiAffinityGroup)
+		if err := d.placeDevice(client); err != nil {
+			return err
+		}
+	}
+
+	if d.TTL > 0 {
+		d.ExpiresAt = now().Add(d.TTL).UTC().Format(time.RFC3339)
+		log.Infof("Xelon device expires at %v", d.ExpiresAt)
+	}
+
+	if d.DryRun {
+		return d.dryRunCreate(client)
+	}
+
+	claimed := false
+	if d.UsePool {
+		log.Infof("Claiming a Xelon device from pool %v...", d.Pool)
+		if claimed, err = d.claimPoolDevice(client); err != nil {
+			return err
+		}
+		if !claimed {
+			log.Infof("Pool %v is empty, creating a new Xelon device", d.Pool)
+		}
+	}
+	if claimed {
+		if err := d.takeOverPoolDevice(client); err != nil {
+			return err
+		}
+	} else if err := d.createNewDevice(client); err != nil {
+		return err
+	}
+
+	log.Info("Waiting until Xelon device has an IP address...")
+	if err := d.waitForIPAddress(); err != nil {
+		return err
+	}
+
+	log.Info("Opening SSH and Docker engine ports in the firewall...")
+	err = d.ensureFirewallRules(client)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Adding SSH key to the device...")
+	err = d.addSSHKey(d.LocalVMID)
+	if err != nil {
+		return err
+	}
+
+	if len(d.DataDisks) > 0 {
+		log.Info("Attaching data disks to the device...")
+		if err := d.attachDataDisks(client); err != nil {
+			return err
+		}
+	}
+
+	log.Info("Starting Xelon device...")
+	err = d.startDevice()
+	if err != nil {
+		return err
+	}
+
+	if len(d.DataDisks) > 0 {
+		log.Info("Formatting and mounting data disks...")
+		if err := d.waitForSSH(); err != nil {
+			return err
+		}
+		if err := d.prepareDataDisks(); err != nil {
+			return err
+		}
+	}
+
+	if d.DNSZone != "" {
+		log.Infof("Registering %v.%v in DNS...", d.MachineName, d.DNSZone)
+		if err := d.registerDNSRecords(client); err != nil {
+			return err
+		}
+	}
+
+	log.Debugf("Created device LocalVMID %v, IP address %v", d.LocalVMID, d.IPAddress)
+
+	return nil
+}
+
+// createNewDevice creates the device of the machine and waits until it is provisioned.
+func (d *Driver) createNewDevice(client *api.Client) error {
+	if d.StaticIPAddress != "" {
+		log.Infof("Reserving IP address %v...", d.StaticIPAddress)
+		if err := d.reserveIPAddress(client); err != nil {
+			return err
+		}
+	}
+
 	log.Info("Creating Xelon device...")
 	deviceCreateResponse, err := d.createDevice()
 	if err != nil {
+		if releaseErr := d.releaseIPAddress(client); releaseErr != nil {
+			log.Warn(releaseErr)
+		}
 		return err
 	}
 	log.Debugf("DeviceCreateResponse: %+v", deviceCreateResponse)
 
 	d.LocalVMID = deviceCreateResponse.Device.LocalVMID
-	d.IPAddress = deviceCreateResponse.IPs[0]
 
 	log.Info("Waiting until Xelon device will be provisioned...")
 	retryCount := 5
 	currentRetry := 1
 	for {
-		deviceRoot, _, err := client.Devices.Get(tenant.TenantIdentifier, deviceCreateResponse.Device.LocalVMID)
+		deviceRoot, _, err := client.Devices.Get(d.TenantID, deviceCreateResponse.Device.LocalVMID)
 		if err != nil {
 			log.Debugf("Error by getting device information: retry %v of %v", currentRetry, retryCount)
 			if currentRetry <= retryCount {
 				currentRetry++
 				log.Debug("Waiting 5 seconds before next call...")
-				time.Sleep(5 * time.Second)
+				sleep(5 * time.Second)
 				continue
 			}
 			log.Info("Xelon device could not created, clean up resources...")
@@ -94,29 +261,27 @@ func (d *Driver) Create() error {
 		device := deviceRoot.Device
 		toolsStatus := deviceRoot.ToolsStatus
 		log.Debugf("device.powerstate: %v, device.state: %v, tools.runningStatus: %v", device.Powerstate, device.LocalVMDetails.State, toolsStatus.RunningStatus)
-		if device.Powerstate == true && device.LocalVMDetails.State == 1 && toolsStatus.RunningStatus == "guestToolsRunning" {
+		if device.Powerstate == true && device.LocalVMDetails.State == 1 && toolsStatus.RunningStatus == guestToolsRunning {
+			if d.HVSystemID != 0 && device.LocalVMDetails.HVSystemID != 0 && device.LocalVMDetails.HVSystemID != d.HVSystemID {
+				err := fmt.Errorf("device was placed on hypervisor system %d instead of %d",
+					device.LocalVMDetails.HVSystemID, d.HVSystemID)
+				if deleteErr := d.deleteMisplacedDevice(client); deleteErr != nil {
+					log.Warn(deleteErr)
+				}
+				return err
+			}
 			break
 		}
-		time.Sleep(2 * time.Second)
+		sleep(2 * time.Second)
 	}
 
 	log.Debug("(workaround): waiting 15 seconds to be sure that server is ready...")
-	time.Sleep(15 * time.Second)
-
-	log.Info("Adding SSH key to the device...")
-	err = d.addSSHKey(d.LocalVMID)
-	if err != nil {
-		return err
-	}
+	sleep(15 * time.Second)
 
-	log.Info("Starting Xelon device...")
-	err = d.startDevice()
-	if err != nil {
-		return err
+	if d.cloneSourceVMID != "" {
+		return d.adoptDeviceResources(client)
 	}
 
-	log.Debugf("Created device LocalVMID %v, IP address %v", d.LocalVMID, d.IPAddress)
-
 	return nil
 }
 
@@ -126,28 +291,126 @@ func (d *Driver) DriverName() string {
 
 func (d *Driver) GetCreateFlags() []mcnflag.Flag {
 	return []mcnflag.Flag{
+		mcnflag.BoolFlag{
+			EnvVar: "XELON_ALLOW_DUPLICATE_NAME",
+			Name:   "xelon-allow-duplicate-name",
+			Usage:  "Allow creating a device even if a device with the same hostname already exists",
+		},
+		mcnflag.StringFlag{
+			EnvVar: "XELON_ALLOWED_SOURCE_CIDR",
+			Name:   "xelon-allowed-source-cidr",
+			Usage:  "Source network in CIDR notation which is allowed to access the SSH and Docker engine ports",
+			Value:  defaultAllowedSourceCIDR,
+		},
+		mcnflag.StringFlag{
+			EnvVar: "XELON_ANTI_AFFINITY_GROUP",
+			Name:   "xelon-anti-affinity-group",
+			Usage:  "Name of a group of machines which are placed on different hypervisor systems",
+		},
 		mcnflag.StringFlag{
 			EnvVar: "XELON_API_BASE_URL",
 			Name:   "xelon-api-base-url",
 			Usage:  "Xelon API base URL",
 		},
+		mcnflag.StringFlag{
+			EnvVar: "XELON_CLONE_FROM",
+			Name:   "xelon-clone-from",
+			Usage:  "Name or ID of a template, or localvmid of a device, from which the device is created",
+		},
 		mcnflag.IntFlag{
 			EnvVar: "XELON_CPU_CORES",
 			Name:   "xelon-cpu-cores",
-			Usage:  "Number of CPU cores for the device",
-			Value:  defaultCPUCores,
+			Usage:  fmt.Sprintf("Number of CPU cores for the device (default: %d)", defaultCPUCores),
+		},
+		mcnflag.StringFlag{
+			EnvVar: "XELON_CREDENTIAL_HELPER",
+			Name:   "xelon-credential-helper",
+			Usage:  "Name of the credential helper (xelon-credential-<name>) which provides the Xelon authentication token",
+		},
+		mcnflag.StringSliceFlag{
+			Name:  "xelon-data-disk",
+			Usage: "Additional data disk in the form size=<GB>,mount=<path>[,fs=ext4|xfs], can be repeated",
+		},
+		mcnflag.BoolFlag{
+			EnvVar: "XELON_DELETE_PROTECTION",
+			Name:   "xelon-delete-protection",
+			Usage:  "Protect the machine against removal until the protection is disabled",
 		},
 		mcnflag.StringFlag{
 			EnvVar: "XELON_DEVICE_PASSWORD",
 			Name:   "xelon-device-password",
-			Usage:  "Password for the device",
-			Value:  defaultDevicePassword,
+			Usage:  "Password for the device, a random password is generated if not set",
+		},
+		mcnflag.StringFlag{
+			EnvVar: "XELON_DEVICE_PASSWORD_FILE",
+			Name:   "xelon-device-password-file",
+			Usage:  "Path to a file containing the password for the device",
+		},
+		mcnflag.IntFlag{
+			EnvVar: "XELON_DEVICE_PASSWORD_MIN_CHARACTER_CLASSES",
+			Name:   "xelon-device-password-min-character-classes",
+			Usage:  "Minimal number of character classes (lowercase, uppercase, digits, symbols) in the device password",
+			Value:  defaultPasswordMinCharacterClasses,
+		},
+		mcnflag.IntFlag{
+			EnvVar: "XELON_DEVICE_PASSWORD_MIN_LENGTH",
+			Name:   "xelon-device-password-min-length",
+			Usage:  "Minimal length of the device password",
+			Value:  defaultPasswordMinLength,
 		},
 		mcnflag.IntFlag{
 			EnvVar: "XELON_DISK_SIZE",
 			Name:   "xelon-disk-size",
-			Usage:  "Drive size for the device in GB",
-			Value:  defaultDiskSize,
+			Usage:  fmt.Sprintf("Drive size for the device in GB (default: %d)", defaultDiskSize),
+		},
+		mcnflag.IntFlag{
+			EnvVar: "XELON_DNS_TTL",
+			Name:   "xelon-dns-ttl",
+			Usage:  "TTL in seconds of the DNS records of the machine",
+			Value:  defaultDNSTTL,
+		},
+		mcnflag.StringFlag{
+			EnvVar: "XELON_DNS_ZONE",
+			Name:   "xelon-dns-zone",
+			Usage:  "DNS zone in which the machine is registered as <machine>.<zone>",
+		},
+		mcnflag.BoolFlag{
+			EnvVar: "XELON_DRY_RUN",
+			Name:   "xelon-dry-run",
+			Usage:  "Show the API requests which would create the device instead of sending them",
+		},
+		mcnflag.IntFlag{
+			EnvVar: "XELON_ENGINE_PORT",
+			Name:   "xelon-engine-port",
+			Usage:  "Port of the Docker engine",
+			Value:  defaultEnginePort,
+		},
+		mcnflag.IntFlag{
+			EnvVar: "XELON_HV_SYSTEM_ID",
+			Name:   "xelon-hv-system-id",
+			Usage:  "ID of the hypervisor system for the device",
+		},
+		mcnflag.StringFlag{
+			EnvVar: "XELON_IP_ADDRESS",
+			Name:   "xelon-ip-address",
+			Usage:  "Static IP address for the device, which is reserved in the network given by xelon-network-id",
+		},
+		mcnflag.StringFlag{
+			EnvVar: "XELON_IP_FAMILY",
+			Name:   "xelon-ip-family",
+			Usage:  "Family of the IP address which is used to connect to the device: ipv4, ipv6 or prefer-ipv6",
+			Value:  ipFamilyIPv4,
+		},
+		mcnflag.StringFlag{
+			EnvVar: "XELON_IP_VISIBILITY",
+			Name:   "xelon-ip-visibility",
+			Usage:  "Visibility of the IP address which is used to connect to the device: any, private or public",
+			Value:  ipVisibilityAny,
+		},
+		mcnflag.BoolFlag{
+			EnvVar: "XELON_KEEP_IP",
+			Name:   "xelon-keep-ip",
+			Usage:  "Keep the reservation of the static IP address when the machine is removed",
 		},
 		mcnflag.StringFlag{
 			EnvVar: "XELON_KUBERNETES_ID",
@@ -158,8 +421,54 @@ func (d *Driver) GetCreateFlags() []mcnflag.Flag {
 		mcnflag.IntFlag{
 			EnvVar: "XELON_MEMORY",
 			Name:   "xelon-memory",
-			Usage:  "Size of memory for the device in GB",
-			Value:  defaultMemory,
+			Usage:  fmt.Sprintf("Size of memory for the device in GB (default: %d)", defaultMemory),
+		},
+		mcnflag.IntFlag{
+			EnvVar: "XELON_NETWORK_ID",
+			Name:   "xelon-network-id",
+			Usage:  "Network ID for the device",
+		},
+		mcnflag.StringFlag{
+			EnvVar: "XELON_NIC_LABEL",
+			Name:   "xelon-nic-label",
+			Usage:  "Label of the network interface whose IP address is used to connect to the device, e.g. eth1",
+		},
+		mcnflag.StringFlag{
+			EnvVar: "XELON_PLAN",
+			Name:   "xelon-plan",
+			Usage:  "Name of the plan with a predefined set of device resources (small, medium, large, large-ci or custom plan)",
+		},
+		mcnflag.StringFlag{
+			EnvVar: "XELON_PLAN_CATALOG",
+			Name:   "xelon-plan-catalog",
+			Usage:  "Path to a YAML or JSON file with custom plans",
+		},
+		mcnflag.StringFlag{
+			EnvVar: "XELON_POOL",
+			Name:   "xelon-pool",
+			Usage:  "Name of the pool of pre-created devices which is used with xelon-use-pool",
+			Value:  defaultPool,
+		},
+		mcnflag.StringFlag{
+			EnvVar: "XELON_PROFILE",
+			Name:   "xelon-profile",
+			Usage:  "Name of the profile in the xelon config file",
+		},
+		mcnflag.StringFlag{
+			EnvVar: "XELON_PUBLIC_ENDPOINT",
+			Name:   "xelon-public-endpoint",
+			Usage:  "Public host name or IP address to connect to the device, the device IP address is used if not set",
+		},
+		mcnflag.BoolFlag{
+			EnvVar: "XELON_SNAPSHOT_ON_STOP",
+			Name:   "xelon-snapshot-on-stop",
+			Usage:  "Take a snapshot of the device every time it is stopped",
+		},
+		mcnflag.IntFlag{
+			EnvVar: "XELON_SNAPSHOT_RETENTION",
+			Name:   "xelon-snapshot-retention",
+			Usage:  "Number of snapshots taken on stop which are kept",
+			Value:  defaultSnapshotRetention,
 		},
 		mcnflag.IntFlag{
 			EnvVar: "XELON_SSH_PORT",
@@ -176,14 +485,37 @@ func (d *Driver) GetCreateFlags() []mcnflag.Flag {
 		mcnflag.IntFlag{
 			EnvVar: "XELON_SWAP_DISK_SIZE",
 			Name:   "xelon-swap-disk-size",
-			Usage:  "Swap disk size for the device in GB",
-			Value:  defaultSwapDiskSize,
+			Usage:  fmt.Sprintf("Swap disk size for the device in GB (default: %d)", defaultSwapDiskSize),
+		},
+		mcnflag.StringSliceFlag{
+			Name:  "xelon-tag",
+			Usage: "Tag for the device in the form key=value, can be repeated",
+		},
+		mcnflag.IntFlag{
+			EnvVar: "XELON_TEMPLATE_ID",
+			Name:   "xelon-template-id",
+			Usage:  "Template ID for the device",
+		},
+		mcnflag.StringFlag{
+			EnvVar: "XELON_TENANT_ID",
+			Name:   "xelon-tenant-id",
+			Usage:  "Tenant ID for the device, the tenant of the token is used if not set",
 		},
 		mcnflag.StringFlag{
 			EnvVar: "XELON_TOKEN",
 			Name:   "xelon-token",
 			Usage:  "Xelon authentication token",
 		},
+		mcnflag.StringFlag{
+			EnvVar: "XELON_TTL",
+			Name:   "xelon-ttl",
+			Usage:  "Time to live of the device, e.g. 6h, after which it is expired and can be reaped",
+		},
+		mcnflag.BoolFlag{
+			EnvVar: "XELON_USE_POOL",
+			Name:   "xelon-use-pool",
+			Usage:  "Claim a stopped device from the pool instead of creating a new one, if the pool is not empty",
+		},
 	}
 }
 
@@ -196,16 +528,21 @@ func (d *Driver) GetURL() (string, error) {
 		return "", err
 	}
 
-	ip, err := d.GetIP()
+	host, err := d.GetSSHHostname()
 	if err != nil {
 		return "", err
 	}
 
-	return fmt.Sprintf("tcp://%s", net.JoinHostPort(ip, "2376")), nil
+	return fmt.Sprintf("tcp://%s", net.JoinHostPort(host, strconv.Itoa(d.getEnginePort()))), nil
 }
 
 func (d *Driver) GetState() (state.State, error) {
-	deviceRoot, _, err := d.getClient().Devices.Get(d.TenantID, d.LocalVMID)
+	client, err := d.getClient()
+	if err != nil {
+		return state.Error, err
+	}
+
+	deviceRoot, _, err := client.Devices.Get(d.TenantID, d.LocalVMID)
 	if err != nil {
 		return state.Error, err
 	}
@@ -219,7 +556,7 @@ func (d *Driver) GetState() (state.State, error) {
 	if device.Powerstate == false {
 		return state.Stopped, nil
 	} else {
-		if device.LocalVMDetails.State == 1 && toolsStatus.RunningStatus == "guestToolsRunning" {
+		if device.LocalVMDetails.State == 1 && toolsStatus.RunningStatus == guestToolsRunning {
 			return state.Running, nil
 		} else {
 			return state.Starting, nil
@@ -228,26 +565,100 @@ func (d *Driver) GetState() (state.State, error) {
 }
 
 func (d *Driver) Kill() error {
-	_, err := d.getClient().Devices.Stop(d.LocalVMID)
+	client, err := d.getClient()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Devices.Stop(d.LocalVMID)
 	return err
 }
 
 func (d *Driver) PreCreateCheck() error {
-	if len(d.DevicePassword) < 6 {
-		return fmt.Errorf("xelon-device-password must be at least 6 characters long")
+	if d.DevicePasswordMinCharacterClasses < 1 || d.DevicePasswordMinCharacterClasses > 4 {
+		return fmt.Errorf("xelon-device-password-min-character-classes must be between 1 and 4")
+	}
+	if err := validatePassword(d.DevicePassword, d.DevicePasswordMinLength, d.DevicePasswordMinCharacterClasses); err != nil {
+		return err
+	}
+	if err := validateHostname(d.MachineName); err != nil {
+		return err
+	}
+
+	log.Info("Validating Xelon token...")
+	client, err := d.getClient()
+	if err != nil {
+		return err
+	}
+	tenant, resp, err := client.Tenant.Get()
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusUnauthorized {
+			return fmt.Errorf("xelon-token is not valid: %v", err)
+		}
+		return err
+	}
+	if d.TenantID == "" {
+		d.TenantID = tenant.TenantIdentifier
 	}
+
+	if err := d.checkResourceLimits(client); err != nil {
+		return err
+	}
+
+	if !d.AllowDuplicateName {
+		if err := d.checkHostnameCollision(client); err != nil {
+			return err
+		}
+	}
+
+	if d.DNSZone != "" {
+		if err := d.checkDNSZone(client); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 func (d *Driver) Remove() error {
+	if d.LocalVMID == "" && d.ReservedIPAddress == "" {
+		// e.g. after a dry run or a create which failed before the device was created
+		log.Info("Xelon device was never created, nothing to remove")
+		return nil
+	}
+
+	client, err := d.getClient()
+	if err != nil {
+		return err
+	}
+	if err := d.checkDeleteProtection(client); err != nil {
+		return err
+	}
+
 	log.Info("Stopping Xelon device...")
-	err := d.stopDevice()
+	err = d.stopDevice()
+	if err != nil {
+		// a device which doesn't exist anymore is handled when it is deleted
+		if errResp, ok := err.(*api.ErrorResponse); !ok || errResp.Response.StatusCode != http.StatusNotFound {
+			return err
+		}
+	}
+
+	log.Info("Deleting firewall rules...")
+	err = d.removeFirewallRules(client)
 	if err != nil {
 		return err
 	}
 
+	if len(d.DNSRecords) > 0 {
+		log.Info("Deleting DNS records...")
+		err = d.removeDNSRecords(client)
+		if err != nil {
+			return err
+		}
+	}
+
 	log.Info("Deleting Xelon device...")
-	client := d.getClient()
 	if resp, err := client.Devices.Delete(d.LocalVMID); err != nil {
 		if resp != nil && resp.StatusCode == http.StatusNotFound {
 			log.Info("Xelon device doesn't exist, assuming it is already deleted")
@@ -256,31 +667,207 @@ func (d *Driver) Remove() error {
 		}
 	}
 
+	if d.ReservedIPAddress != "" {
+		log.Info("Releasing IP address reservation...")
+		err = d.releaseIPAddress(client)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 func (d *Driver) Restart() error {
-	err := d.stopDevice()
+	client, err := d.getClient()
 	if err != nil {
 		return err
 	}
-	return d.startDevice()
+
+	deviceRoot, _, err := client.Devices.Get(d.TenantID, d.LocalVMID)
+	if err != nil {
+		return err
+	}
+
+	if deviceRoot.Device.Powerstate && deviceRoot.ToolsStatus.RunningStatus == guestToolsRunning {
+		log.Debug("Rebooting Xelon device...")
+		_, err = client.Devices.Reboot(d.LocalVMID)
+		if err != nil {
+			return err
+		}
+		d.waitForRebootStarted()
+	} else {
+		log.Debug("Guest tools are not running, stopping and starting Xelon device...")
+		err = d.stopDevice()
+		if err != nil {
+			return err
+		}
+		err = d.startDevice()
+		if err != nil {
+			return err
+		}
+	}
+
+	return d.waitForDeviceRunning()
 }
 
 func (d *Driver) SetConfigFromFlags(opts drivers.DriverOptions) error {
-	d.APIBaseURL = opts.String("xelon-api-base-url")
-	d.CPUCores = opts.Int("xelon-cpu-cores")
+	// options are resolved in the following order: flag, environment variable, plan (for device resources),
+	// profile, default value
+	d.Profile = opts.String("xelon-profile")
+	// IPv6 literals may be given in brackets as in URLs
+	d.PublicEndpoint = strings.TrimSuffix(strings.TrimPrefix(opts.String("xelon-public-endpoint"), "["), "]")
+	profile, err := loadProfile(d.Profile)
+	if err != nil {
+		return err
+	}
+	d.Plan = firstString(opts.String("xelon-plan"), profile.Plan)
+	plan, err := resolvePlan(d.Plan, firstString(opts.String("xelon-plan-catalog"), profile.PlanCatalog))
+	if err != nil {
+		return err
+	}
+
+	d.AllowDuplicateName = opts.Bool("xelon-allow-duplicate-name")
+	d.AllowedSourceCIDR = opts.String("xelon-allowed-source-cidr")
+	d.AntiAffinityGroup = opts.String("xelon-anti-affinity-group")
+	d.APIBaseURL = firstString(opts.String("xelon-api-base-url"), profile.APIBaseURL)
+	d.CloneFrom = opts.String("xelon-clone-from")
+	d.CPUCores = firstInt(opts.Int("xelon-cpu-cores"), plan.CPUCores, profile.CPUCores, defaultCPUCores)
+	d.CredentialHelper = opts.String("xelon-credential-helper")
+	d.DeleteProtection = opts.Bool("xelon-delete-protection")
 	d.DevicePassword = opts.String("xelon-device-password")
-	d.DiskSize = opts.Int("xelon-disk-size")
+	d.DevicePasswordMinCharacterClasses = opts.Int("xelon-device-password-min-character-classes")
+	d.DevicePasswordMinLength = opts.Int("xelon-device-password-min-length")
+	d.DiskSize = firstInt(opts.Int("xelon-disk-size"), plan.DiskSize, profile.DiskSize, defaultDiskSize)
+	d.DNSTTL = opts.Int("xelon-dns-ttl")
+	d.DNSZone = normalizeZoneName(opts.String("xelon-dns-zone"))
+	d.DryRun = opts.Bool("xelon-dry-run")
+	d.EnginePort = opts.Int("xelon-engine-port")
+	d.HVSystemID = opts.Int("xelon-hv-system-id")
+	d.IPFamily = opts.String("xelon-ip-family")
+	d.IPVisibility = opts.String("xelon-ip-visibility")
+	d.KeepIP = opts.Bool("xelon-keep-ip")
 	d.KubernetesID = opts.String("xelon-kubernetes-id")
-	d.Memory = opts.Int("xelon-memory")
+	d.Memory = firstInt(opts.Int("xelon-memory"), plan.Memory, profile.Memory, defaultMemory)
+	d.NetworkID = firstInt(opts.Int("xelon-network-id"), profile.NetworkID)
+	d.NICLabel = opts.String("xelon-nic-label")
+	d.Pool = opts.String("xelon-pool")
+	d.SnapshotOnStop = opts.Bool("xelon-snapshot-on-stop")
+	d.SnapshotRetention = opts.Int("xelon-snapshot-retention")
 	d.SSHPort = opts.Int("xelon-ssh-port")
+	d.StaticIPAddress = opts.String("xelon-ip-address")
 	d.SSHUser = opts.String("xelon-ssh-user")
-	d.SwapDiskSize = opts.Int("xelon-swap-disk-size")
+	d.SwapDiskSize = firstInt(opts.Int("xelon-swap-disk-size"), plan.SwapDiskSize, profile.SwapDiskSize, defaultSwapDiskSize)
+	d.TemplateID = firstInt(opts.Int("xelon-template-id"), profile.TemplateID)
+	d.TenantID = firstString(opts.String("xelon-tenant-id"), profile.TenantID)
 	d.Token = opts.String("xelon-token")
+	d.UsePool = opts.Bool("xelon-use-pool")
+
+	// the token source of the profile is only used if none is given explicitly. The token of the profile is
+	// not copied, so it is not written to the config.json of the machine, getClient reads it from the profile.
+	profileToken := ""
+	if d.Token == "" && d.CredentialHelper == "" {
+		profileToken = profile.Token
+		d.CredentialHelper = profile.CredentialHelper
+	}
+
+	if d.Token == "" && profileToken == "" && d.CredentialHelper == "" {
+		return fmt.Errorf("xelon driver requires the --xelon-token or --xelon-credential-helper option")
+	}
+	if (d.Token != "" || profileToken != "") && d.CredentialHelper != "" {
+		return fmt.Errorf("--xelon-token and --xelon-credential-helper cannot be used together")
+	}
+	if d.CredentialHelper != "" {
+		if err := validateCredentialHelperName(d.CredentialHelper); err != nil {
+			return err
+		}
+	}
+
+	if err := validateCIDR(d.AllowedSourceCIDR); err != nil {
+		return err
+	}
+	if d.DNSZone != "" && d.DNSTTL < 1 {
+		return fmt.Errorf("xelon-dns-ttl must be at least 1")
+	}
+	if d.EnginePort < 1 || d.EnginePort > 65535 {
+		return fmt.Errorf("xelon-engine-port must be between 1 and 65535")
+	}
+	if d.StaticIPAddress != "" {
+		if err := validateStaticIPAddress(d.StaticIPAddress, d.NetworkID); err != nil {
+			return err
+		}
+	}
+	if err := validateIPFamily(d.IPFamily); err != nil {
+		return err
+	}
+	if err := validateIPVisibility(d.IPVisibility); err != nil {
+		return err
+	}
+	if d.PublicEndpoint != "" {
+		if err := validateEndpoint(d.PublicEndpoint); err != nil {
+			return err
+		}
+	}
+	if err := d.validateEnginePortFamily(); err != nil {
+		return err
+	}
+
+	if d.CloneFrom != "" {
+		if opts.Int("xelon-template-id") != 0 || d.StaticIPAddress != "" {
+			return fmt.Errorf("--xelon-clone-from cannot be used together with --xelon-template-id or --xelon-ip-address")
+		}
+		// the clone source takes precedence over the template of the profile
+		d.TemplateID = 0
+	}
+	if err := d.validatePoolOptions(); err != nil {
+		return err
+	}
+
+	if d.SnapshotOnStop && d.SnapshotRetention < 1 {
+		return fmt.Errorf("xelon-snapshot-retention must be at least 1")
+	}
+
+	if ttl := opts.String("xelon-ttl"); ttl != "" {
+		d.TTL, err = time.ParseDuration(ttl)
+		if err != nil || d.TTL <= 0 {
+			return fmt.Errorf("invalid xelon-ttl %q, expected a positive duration like 6h or 90m", ttl)
+		}
+	}
+
+	tags, err := parseTags(opts.StringSlice("xelon-tag"))
+	if err != nil {
+		return err
+	}
+	d.Tags = tags
+
+	dataDisks, err := parseDataDisks(opts.StringSlice("xelon-data-disk"))
+	if err != nil {
+		return err
+	}
+	d.DataDisks = dataDisks
 
-	if d.Token == "" {
-		return fmt.Errorf("xelon driver requires the --xelon-token option")
+	if devicePasswordFile := opts.String("xelon-device-password-file"); devicePasswordFile != "" {
+		if d.DevicePassword != "" {
+			return fmt.Errorf("--xelon-device-password and --xelon-device-password-file cannot be used together")
+		}
+		password, err := readPasswordFile(devicePasswordFile)
+		if err != nil {
+			return err
+		}
+		d.DevicePassword = password
+	}
+
+	if d.DevicePassword == "" {
+		// the generated password is persisted in the machine's config.json which is only readable by its owner
+		length := generatedPasswordLength
+		if d.DevicePasswordMinLength > length {
+			length = d.DevicePasswordMinLength
+		}
+		password, err := generatePassword(length)
+		if err != nil {
+			return fmt.Errorf("could not generate device password: %v", err)
+		}
+		d.DevicePassword = password
 	}
 
 	return nil
@@ -291,32 +878,85 @@ func (d *Driver) Start() error {
 }
 
 func (d *Driver) Stop() error {
-	return d.stopDevice()
+	err := d.stopDevice()
+	if err != nil {
+		return err
+	}
+
+	if d.SnapshotOnStop {
+		return d.snapshotOnStop()
+	}
+	return nil
+}
+
+// getEnginePort returns the port of the Docker engine. Machines created before the port was configurable
+// use the default port.
+func (d *Driver) getEnginePort() int {
+	if d.EnginePort == 0 {
+		return defaultEnginePort
+	}
+	return d.EnginePort
 }
 
-func (d *Driver) getClient() *api.Client {
-	client := api.NewClient(d.Token)
+func (d *Driver) getClient() (*api.Client, error) {
+	token := d.Token
+	if token == "" && d.CredentialHelper == "" {
+		p, err := loadProfile(d.Profile)
+		if err != nil {
+			return nil, err
+		}
+		token = p.Token
+	}
+	client := api.NewClient(token)
+	client.Recorder = d.recorder
 	if d.APIBaseURL != "" {
 		client.SetBaseURL(d.APIBaseURL)
 	}
-	return client
+	if d.CredentialHelper != "" {
+		token, err := tokenFromCredentialHelper(d.CredentialHelper, client.BaseURL.String())
+		if err != nil {
+			return nil, err
+		}
+		client.Token = token
+	}
+	return client, nil
 }
 
 func (d *Driver) createDevice() (*api.DeviceCreateResponse, error) {
-	deviceCreateConfiguration := &api.DeviceCreateConfiguration{
+	if d.cloneSourceVMID != "" {
+		return d.cloneDevice(d.deviceTags())
+	}
+	return d.createDeviceWithConfiguration(d.deviceCreateConfiguration())
+}
+
+// deviceCreateConfiguration returns the configuration of the device of the machine.
+func (d *Driver) deviceCreateConfiguration() *api.DeviceCreateConfiguration {
+	return &api.DeviceCreateConfiguration{
 		CPUCores:     d.CPUCores,
 		DiskSize:     d.DiskSize,
 		DisplayName:  d.MachineName,
 		Hostname:     d.MachineName,
+		HVSystemID:   d.HVSystemID,
+		IPAddress:    d.StaticIPAddress,
 		KubernetesID: d.KubernetesID,
 		Memory:       d.Memory,
+		NetworkID:    d.NetworkID,
 		Password:     d.DevicePassword,
 		SwapDiskSize: d.SwapDiskSize,
+		Tags:         d.deviceTags(),
+		TemplateID:   d.TemplateID,
 	}
+}
 
-	log.Debugf("Creating Xelon device with configuration: %+v", deviceCreateConfiguration)
+func (d *Driver) createDeviceWithConfiguration(deviceCreateConfiguration *api.DeviceCreateConfiguration) (*api.DeviceCreateResponse, error) {
+	redactedConfiguration := *deviceCreateConfiguration
+	redactedConfiguration.Password = "REDACTED"
+	log.Debugf("Creating Xelon device with configuration: %+v", redactedConfiguration)
 
-	client := d.getClient()
+	client, err := d.getClient()
+	if err != nil {
+		return nil, err
+	}
 	deviceCreateResponse, _, err := client.Devices.Create(deviceCreateConfiguration)
 	if err != nil {
 		return deviceCreateResponse, err
@@ -341,7 +981,11 @@ func (d *Driver) addSSHKey(localVMID string) error {
 		Name:   d.MachineName,
 		SSHKey: string(publicKey),
 	}
-	_, err = d.getClient().SSHs.Add(localVMID, sshCreateConfiguration)
+	client, err := d.getClient()
+	if err != nil {
+		return err
+	}
+	_, err = client.SSHs.Add(localVMID, sshCreateConfiguration)
 	if err != nil {
 		return err
 	}
@@ -350,7 +994,10 @@ func (d *Driver) addSSHKey(localVMID string) error {
 }
 
 func (d *Driver) startDevice() error {
-	client := d.getClient()
+	client, err := d.getClient()
+	if err != nil {
+		return err
+	}
 
 	log.Debug("Checking device state...")
 	deviceRoot, _, err := client.Devices.Get(d.TenantID, d.LocalVMID)
@@ -374,7 +1021,10 @@ func (d *Driver) startDevice() error {
 }
 
 func (d *Driver) stopDevice() error {
-	client := d.getClient()
+	client, err := d.getClient()
+	if err != nil {
+		return err
+	}
 
 	log.Debug("Checking device state...")
 	deviceRoot, _, err := client.Devices.Get(d.TenantID, d.LocalVMID)
@@ -395,23 +1045,65 @@ func (d *Driver) stopDevice() error {
 	}
 
 	log.Debug("Waiting until device is stopped...")
-	for {
+	for i := 0; i < int(deviceStateTimeout/time.Second); i++ {
 		deviceRoot, _, err := client.Devices.Get(d.TenantID, d.LocalVMID)
 		if err != nil {
 			return nil
 		}
 		if deviceRoot.Device.Powerstate == false {
-			break
+			return nil
 		}
-		time.Sleep(1 * time.Second)
+		sleep(1 * time.Second)
 	}
 
-	return nil
+	return fmt.Errorf("timeout waiting for Xelon device %v to be stopped", d.LocalVMID)
+}
+
+// waitForRebootStarted waits until the guest tools stop running because of the reboot, so the device is not
+// reported as running before the reboot has actually started. The reboot may be too fast to be observed,
+// therefore the wait ends after rebootStartTimeout without an error.
+func (d *Driver) waitForRebootStarted() {
+	for i := 0; i < int(rebootStartTimeout/deviceStatePollInterval); i++ {
+		s, err := d.GetState()
+		if err == nil && s != state.Running {
+			return
+		}
+		sleep(deviceStatePollInterval)
+	}
+	log.Debug("Reboot of Xelon device was not observed, assuming it is already finished")
+}
+
+// waitForDeviceRunning waits until the device is powered on and its guest tools are running.
+func (d *Driver) waitForDeviceRunning() error {
+	log.Debug("Waiting until device is running...")
+	for i := 0; i < int(deviceStateTimeout/deviceStatePollInterval); i++ {
+		s, err := d.GetState()
+		if err != nil {
+			return err
+		}
+		if s == state.Running {
+			return nil
+		}
+		sleep(deviceStatePollInterval)
+	}
+	return fmt.Errorf("timeout waiting for Xelon device %v to be running", d.LocalVMID)
+}
+
+func (d *Driver) waitForSSH() error {
+	log.Debug("Waiting until SSH is available...")
+	var err error
+	for i := 0; i < 60; i++ {
+		if _, err = runSSHCommand(d, "exit 0"); err == nil {
+			return nil
+		}
+		sleep(5 * time.Second)
+	}
+	return fmt.Errorf("too many retries waiting for SSH to be available, last error: %v", err)
 }
 
 func randomDelay() {
 	rand.Seed(time.Now().UnixNano())
 	n := rand.Intn(10)
 	log.Debugf("random delay is %d", n)
-	time.Sleep(time.Duration(n) * time.Second)
+	sleep(time.Duration(n) * time.Second)
 }
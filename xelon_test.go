@@ -1,12 +1,125 @@
 package xelon
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/docker/machine/libmachine/drivers"
 	"github.com/stretchr/testify/assert"
 )
 
+const testDeviceRoot = `{
+	"device": {
+		"cpu": 2,
+		"disksize": 20,
+		"ram": 2,
+		"localvmdetails": {"localvmid": "localVMID", "state": 1},
+		"networks": [{"ip": "10.0.0.10", "label": "eth0"}],
+		"powerstate": %v
+	},
+	"toolsStatus": {"runningStatus": "guestToolsRunning"}
+}`
+
+// setup starts a fake Xelon API and returns a driver which uses it. The driver's store is a temporary
+// directory, sleeping is disabled and SSH commands are answered by sshStandIn.
+func setup(hostName string) (driver *Driver, mux *http.ServeMux, teardown func()) {
+	mux = http.NewServeMux()
+	var mu sync.Mutex
+	powerstate := true
+	// Default responses which can be overridden by registering a more specific pattern in the test.
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method + " " + r.URL.Path {
+		case "GET /tenant":
+			_, _ = fmt.Fprint(w, `{"tenant_identifier":"tenantID"}`)
+		case "GET /tenant/tenantID/limits":
+			_, _ = fmt.Fprint(w, `{}`)
+		case "GET /vmlist", "GET /firewalls":
+			_, _ = fmt.Fprint(w, `[]`)
+		case "POST /firewalls/create":
+			_, _ = fmt.Fprint(w, `{"id":1}`)
+		case "POST /vmlist/create":
+			_, _ = fmt.Fprint(w, `{"device":{"localvmid":"localVMID"},"ips":["10.0.0.10"]}`)
+		case "GET /device":
+			_, _ = fmt.Fprintf(w, testDeviceRoot, powerstate)
+		case "POST /vmlist/localVMID/startserver":
+			powerstate = true
+			_, _ = fmt.Fprint(w, `{}`)
+		case "POST /vmlist/localVMID/stopserver":
+			powerstate = false
+			_, _ = fmt.Fprint(w, `{}`)
+		case "POST /vmlist/localVMID/ssh/add",
+			"DELETE /vmlist/localVMID":
+			_, _ = fmt.Fprint(w, `{}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	server := httptest.NewServer(mux)
+
+	storePath, err := ioutil.TempDir("", "xelon")
+	if err != nil {
+		panic(err)
+	}
+	_ = os.MkdirAll(filepath.Join(storePath, "machines", hostName), 0700)
+
+	driver = NewDriver(hostName, storePath)
+	driver.APIBaseURL = server.URL + "/"
+	driver.AllowedSourceCIDR = defaultAllowedSourceCIDR
+	driver.CPUCores = defaultCPUCores
+	driver.DevicePassword = "Xelon22-Xelon22"
+	driver.DevicePasswordMinCharacterClasses = defaultPasswordMinCharacterClasses
+	driver.DevicePasswordMinLength = defaultPasswordMinLength
+	driver.DiskSize = defaultDiskSize
+	driver.Memory = defaultMemory
+	driver.SwapDiskSize = defaultSwapDiskSize
+	driver.Token = "token"
+
+	sleep = func(time.Duration) {}
+	standIn := &sshStandIn{}
+	runSSHCommand = standIn.run
+
+	return driver, mux, func() {
+		sleep = time.Sleep
+		runSSHCommand = drivers.RunSSHCommandFromDriver
+		server.Close()
+		_ = os.RemoveAll(storePath)
+	}
+}
+
+// sshStandIn records SSH commands and answers them with scripted outputs. Commands without a scripted
+// output succeed with empty output.
+type sshStandIn struct {
+	commands []string
+	outputs  map[string][]string // command -> outputs returned in order
+}
+
+func (s *sshStandIn) run(d drivers.Driver, command string) (string, error) {
+	s.commands = append(s.commands, command)
+	outputs := s.outputs[command]
+	if len(outputs) == 0 {
+		return "", nil
+	}
+	s.outputs[command] = outputs[1:]
+	return outputs[0], nil
+}
+
+// useSSHStandIn installs a new SSH stand-in with the given scripted outputs.
+func useSSHStandIn(outputs map[string][]string) *sshStandIn {
+	standIn := &sshStandIn{outputs: outputs}
+	runSSHCommand = standIn.run
+	return standIn
+}
+
 func TestDriver_PreCreateCheck_MissingToken(t *testing.T) {
 	driver := NewDriver("default", "path")
 	flags := &drivers.CheckDriverOptions{
@@ -25,7 +138,7 @@ func TestDriver_PreCreateCheck_DevicePasswordLessThen6Characters(t *testing.T) {
 	flags := &drivers.CheckDriverOptions{
 		FlagsValues: map[string]interface{}{
 			"xelon-device-password": "12345",
-			"xelon-token": "token",
+			"xelon-token":           "token",
 		},
 		CreateFlags: driver.GetCreateFlags(),
 	}
@@ -34,3 +147,383 @@ func TestDriver_PreCreateCheck_DevicePasswordLessThen6Characters(t *testing.T) {
 	err := driver.PreCreateCheck()
 	assert.Error(t, err)
 }
+
+func TestDriver_SetConfigFromFlags_GeneratesDevicePassword(t *testing.T) {
+	driver := NewDriver("default", "path")
+	flags := &drivers.CheckDriverOptions{
+		FlagsValues: map[string]interface{}{
+			"xelon-token": "token",
+		},
+		CreateFlags: driver.GetCreateFlags(),
+	}
+
+	err := driver.SetConfigFromFlags(flags)
+
+	assert.NoError(t, err)
+	assert.Len(t, driver.DevicePassword, generatedPasswordLength)
+	assert.NoError(t, validatePassword(driver.DevicePassword, defaultPasswordMinLength, defaultPasswordMinCharacterClasses))
+}
+
+func TestDriver_SetConfigFromFlags_DevicePasswordAndFile(t *testing.T) {
+	driver := NewDriver("default", "path")
+	flags := &drivers.CheckDriverOptions{
+		FlagsValues: map[string]interface{}{
+			"xelon-device-password":      "Secret-Password-1",
+			"xelon-device-password-file": "password.txt",
+			"xelon-token":                "token",
+		},
+		CreateFlags: driver.GetCreateFlags(),
+	}
+
+	err := driver.SetConfigFromFlags(flags)
+
+	assert.Error(t, err)
+}
+
+func TestDriver_SetConfigFromFlags_CredentialHelper(t *testing.T) {
+	driver := NewDriver("default", "path")
+	flags := &drivers.CheckDriverOptions{
+		FlagsValues: map[string]interface{}{
+			"xelon-credential-helper": "vault",
+		},
+		CreateFlags: driver.GetCreateFlags(),
+	}
+
+	err := driver.SetConfigFromFlags(flags)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "vault", driver.CredentialHelper)
+	assert.Empty(t, driver.Token)
+}
+
+func TestDriver_SetConfigFromFlags_TokenAndCredentialHelper(t *testing.T) {
+	driver := NewDriver("default", "path")
+	flags := &drivers.CheckDriverOptions{
+		FlagsValues: map[string]interface{}{
+			"xelon-credential-helper": "vault",
+			"xelon-token":             "token",
+		},
+		CreateFlags: driver.GetCreateFlags(),
+	}
+
+	err := driver.SetConfigFromFlags(flags)
+
+	assert.Error(t, err)
+}
+
+func TestDriver_SetConfigFromFlags_Profile(t *testing.T) {
+	teardown := useConfig(t, testConfig)
+	defer teardown()
+	driver := NewDriver("default", "path")
+	flags := &drivers.CheckDriverOptions{
+		FlagsValues: map[string]interface{}{
+			"xelon-cpu-cores":  8,
+			"xelon-network-id": 5,
+			"xelon-profile":    "ci",
+		},
+		CreateFlags: driver.GetCreateFlags(),
+	}
+
+	err := driver.SetConfigFromFlags(flags)
+
+	assert.NoError(t, err)
+	// flag or environment variable
+	assert.Equal(t, 8, driver.CPUCores)
+	assert.Equal(t, 5, driver.NetworkID)
+	// profile
+	assert.Equal(t, "https://vdc.example.com/api/service/", driver.APIBaseURL)
+	assert.Equal(t, "vault", driver.CredentialHelper)
+	assert.Equal(t, 50, driver.DiskSize)
+	assert.Equal(t, 8, driver.Memory)
+	assert.Equal(t, 12, driver.TemplateID)
+	assert.Equal(t, "tenantID", driver.TenantID)
+	// default value
+	assert.Equal(t, defaultSwapDiskSize, driver.SwapDiskSize)
+}
+
+func TestDriver_SetConfigFromFlags_ProfileToken(t *testing.T) {
+	teardown := useConfig(t, testConfig)
+	defer teardown()
+	driver := NewDriver("default", "path")
+	flags := &drivers.CheckDriverOptions{
+		FlagsValues: map[string]interface{}{},
+		CreateFlags: driver.GetCreateFlags(),
+	}
+
+	err := driver.SetConfigFromFlags(flags)
+
+	assert.NoError(t, err)
+	// the token is read from the profile when it is needed and never stored in the driver
+	assert.Empty(t, driver.Token)
+	data, _ := json.Marshal(driver)
+	assert.NotContains(t, string(data), "default-token")
+	client, err := driver.getClient()
+	assert.NoError(t, err)
+	assert.Equal(t, "default-token", client.Token)
+}
+
+func TestDriver_SetConfigFromFlags_ProfileTokenOverridden(t *testing.T) {
+	teardown := useConfig(t, testConfig)
+	defer teardown()
+	driver := NewDriver("default", "path")
+	flags := &drivers.CheckDriverOptions{
+		FlagsValues: map[string]interface{}{
+			"xelon-profile": "ci",
+			"xelon-token":   "token",
+		},
+		CreateFlags: driver.GetCreateFlags(),
+	}
+
+	err := driver.SetConfigFromFlags(flags)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "token", driver.Token)
+	assert.Empty(t, driver.CredentialHelper)
+}
+
+func TestDriver_SetConfigFromFlags_Defaults(t *testing.T) {
+	teardown := useConfig(t, "")
+	defer teardown()
+	driver := NewDriver("default", "path")
+	flags := &drivers.CheckDriverOptions{
+		FlagsValues: map[string]interface{}{
+			"xelon-token": "token",
+		},
+		CreateFlags: driver.GetCreateFlags(),
+	}
+
+	err := driver.SetConfigFromFlags(flags)
+
+	assert.NoError(t, err)
+	assert.Equal(t, defaultCPUCores, driver.CPUCores)
+	assert.Equal(t, defaultDiskSize, driver.DiskSize)
+	assert.Equal(t, defaultMemory, driver.Memory)
+	assert.Equal(t, defaultSwapDiskSize, driver.SwapDiskSize)
+	assert.Empty(t, driver.TenantID)
+}
+
+func TestDriver_SetConfigFromFlags_Plan(t *testing.T) {
+	teardown := useConfig(t, testConfig)
+	defer teardown()
+	driver := NewDriver("default", "path")
+	flags := &drivers.CheckDriverOptions{
+		FlagsValues: map[string]interface{}{
+			"xelon-memory":  32,
+			"xelon-plan":    "large-ci",
+			"xelon-profile": "ci",
+		},
+		CreateFlags: driver.GetCreateFlags(),
+	}
+
+	err := driver.SetConfigFromFlags(flags)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "large-ci", driver.Plan)
+	assert.Equal(t, 8, driver.CPUCores)
+	assert.Equal(t, 100, driver.DiskSize)
+	assert.Equal(t, 32, driver.Memory)
+	assert.Equal(t, 4, driver.SwapDiskSize)
+}
+
+func TestDriver_Restart_Reboot(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.LocalVMID = "localVMID"
+	driver.TenantID = "tenantID"
+	var calls []string
+	toolsStatus := []string{guestToolsRunning, guestToolsRunning, "guestToolsNotRunning", "guestToolsNotRunning", guestToolsRunning}
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		status := toolsStatus[0]
+		if len(toolsStatus) > 1 {
+			toolsStatus = toolsStatus[1:]
+		}
+		_, _ = fmt.Fprintf(w, `{"device":{"powerstate":true,"localvmdetails":{"state":1}},"toolsStatus":{"runningStatus":"%v"}}`, status)
+	})
+	mux.HandleFunc("/vmlist/localVMID/", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.URL.Path)
+	})
+
+	err := driver.Restart()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/vmlist/localVMID/reboot"}, calls)
+	assert.Equal(t, []string{guestToolsRunning}, toolsStatus)
+}
+
+func TestDriver_Restart_StopStart(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.LocalVMID = "localVMID"
+	driver.TenantID = "tenantID"
+	var calls []string
+	powerstate := true
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		toolsStatus := "guestToolsNotRunning"
+		if powerstate && len(calls) > 1 {
+			toolsStatus = guestToolsRunning
+		}
+		_, _ = fmt.Fprintf(w, `{"device":{"powerstate":%v,"localvmdetails":{"state":1}},"toolsStatus":{"runningStatus":"%v"}}`, powerstate, toolsStatus)
+	})
+	mux.HandleFunc("/vmlist/localVMID/", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.URL.Path)
+		powerstate = r.URL.Path == "/vmlist/localVMID/startserver"
+	})
+
+	err := driver.Restart()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/vmlist/localVMID/stopserver", "/vmlist/localVMID/startserver"}, calls)
+}
+
+func TestDriver_Stop_Timeout(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.LocalVMID = "localVMID"
+	driver.TenantID = "tenantID"
+	mux.HandleFunc("/vmlist/localVMID/stopserver", func(w http.ResponseWriter, r *http.Request) {
+		// device never stops
+	})
+
+	err := driver.Stop()
+
+	assert.Error(t, err)
+}
+
+func TestDriver_GetURL(t *testing.T) {
+	driver, _, teardown := setup("default")
+	defer teardown()
+	driver.IPAddress = "10.0.0.10"
+	driver.LocalVMID = "localVMID"
+	driver.TenantID = "tenantID"
+
+	url, err := driver.GetURL()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "tcp://10.0.0.10:2376", url)
+}
+
+func TestDriver_GetURL_PublicEndpoint(t *testing.T) {
+	driver, _, teardown := setup("default")
+	defer teardown()
+	driver.EnginePort = 12376
+	driver.IPAddress = "10.0.0.10"
+	driver.LocalVMID = "localVMID"
+	driver.PublicEndpoint = "docker.example.com"
+	driver.TenantID = "tenantID"
+
+	url, err := driver.GetURL()
+	assert.NoError(t, err)
+	assert.Equal(t, "tcp://docker.example.com:12376", url)
+
+	hostname, err := driver.GetSSHHostname()
+	assert.NoError(t, err)
+	assert.Equal(t, "docker.example.com", hostname)
+
+	// docker-machine issues the server certificate for the address returned by GetIP
+	ip, err := driver.GetIP()
+	assert.NoError(t, err)
+	assert.Equal(t, "docker.example.com", ip)
+	assert.Equal(t, "10.0.0.10", driver.IPAddress)
+}
+
+func TestDriver_GetURL_IPv6(t *testing.T) {
+	driver, mux, teardown := setup("default")
+	defer teardown()
+	driver.IPFamily = ipFamilyIPv6
+	driver.LocalVMID = "localVMID"
+	driver.TenantID = "tenantID"
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{
+			"device":{"localvmdetails":{"state":1},"networks":[{"ip":"2001:db8::10"}],"powerstate":true},
+			"toolsStatus":{"runningStatus":"guestToolsRunning"}
+		}`)
+	})
+
+	url, err := driver.GetURL()
+	assert.NoError(t, err)
+	assert.Equal(t, "tcp://[2001:db8::10]:2376", url)
+
+	hostname, err := driver.GetSSHHostname()
+	assert.NoError(t, err)
+	assert.Equal(t, "2001:db8::10", hostname)
+}
+
+func TestDriver_GetURL_IPv6PublicEndpoint(t *testing.T) {
+	driver, _, teardown := setup("default")
+	defer teardown()
+	driver.LocalVMID = "localVMID"
+	driver.PublicEndpoint = "2001:db8::20"
+	driver.TenantID = "tenantID"
+
+	url, err := driver.GetURL()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "tcp://[2001:db8::20]:2376", url)
+}
+
+func TestDriver_SetConfigFromFlags_EnginePortAndPublicEndpoint(t *testing.T) {
+	teardown := useConfig(t, "")
+	defer teardown()
+	driver := NewDriver("default", "path")
+	flags := &drivers.CheckDriverOptions{
+		FlagsValues: map[string]interface{}{
+			"xelon-ip-family":       "prefer-ipv6",
+			"xelon-public-endpoint": "[2001:db8::10]",
+			"xelon-token":           "token",
+		},
+		CreateFlags: driver.GetCreateFlags(),
+	}
+
+	err := driver.SetConfigFromFlags(flags)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2376, driver.EnginePort)
+	assert.Equal(t, "2001:db8::10", driver.PublicEndpoint)
+	assert.Equal(t, ipFamilyPreferIPv6, driver.IPFamily)
+}
+
+func TestDriver_SetConfigFromFlags_EnginePortWithHostNameEndpoint(t *testing.T) {
+	teardown := useConfig(t, "")
+	defer teardown()
+	driver := NewDriver("default", "path")
+	flags := &drivers.CheckDriverOptions{
+		FlagsValues: map[string]interface{}{
+			"xelon-engine-port":     12376,
+			"xelon-ip-family":       "ipv6",
+			"xelon-public-endpoint": "docker.example.com",
+			"xelon-token":           "token",
+		},
+		CreateFlags: driver.GetCreateFlags(),
+	}
+
+	err := driver.SetConfigFromFlags(flags)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 12376, driver.EnginePort)
+}
+
+func TestDriver_SetConfigFromFlags_InvalidEnginePortAndPublicEndpoint(t *testing.T) {
+	teardown := useConfig(t, "")
+	defer teardown()
+	tests := map[string]map[string]interface{}{
+		"engine port too low":            {"xelon-engine-port": 0},
+		"engine port too high":           {"xelon-engine-port": 65536},
+		"public endpoint with port":      {"xelon-public-endpoint": "docker.example.com:2376"},
+		"public endpoint with URL":       {"xelon-public-endpoint": "tcp://docker.example.com"},
+		"unknown ip family":              {"xelon-ip-family": "ipv5"},
+		"engine port with ipv6":          {"xelon-engine-port": 12376, "xelon-ip-family": "ipv6"},
+		"engine port with prefer-ipv6":   {"xelon-engine-port": 12376, "xelon-ip-family": "prefer-ipv6"},
+		"engine port with ipv6 endpoint": {"xelon-engine-port": 12376, "xelon-public-endpoint": "2001:db8::10"},
+	}
+	for name, values := range tests {
+		t.Run(name, func(t *testing.T) {
+			driver := NewDriver("default", "path")
+			values["xelon-token"] = "token"
+			flags := &drivers.CheckDriverOptions{FlagsValues: values, CreateFlags: driver.GetCreateFlags()}
+
+			err := driver.SetConfigFromFlags(flags)
+
+			assert.Error(t, err)
+		})
+	}
+}